@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	xpv2 "github.com/crossplane/crossplane-runtime/v2/apis/common/v2"
+)
+
+// A ClusterTenantSpec defines the desired state of a ClusterTenant.
+// +kubebuilder:validation:XValidation:rule="!has(self.providerConfigRef) || self.providerConfigRef.kind == 'ClusterProviderConfig'",message="a ClusterTenant must reference a ClusterProviderConfig"
+// +kubebuilder:validation:XValidation:rule="!has(self.forProvider.groupSets) && !has(self.forProvider.groupSetRefs) && !has(self.forProvider.groupSetSelector)",message="GroupSets are namespaced and cannot be referenced by a ClusterTenant"
+// +kubebuilder:validation:XValidation:rule="!has(self.writeConnectionSecretToRef) && !has(self.forProvider.connectionServiceAccount) && !has(self.forProvider.serviceAccounts)",message="a ClusterTenant has no namespace to write a connection secret to"
+type ClusterTenantSpec struct {
+	xpv2.ManagedResourceSpec `json:",inline"`
+	ForProvider              TenantParameters `json:"forProvider"`
+}
+
+// A ClusterTenantStatus represents the observed state of a ClusterTenant.
+type ClusterTenantStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          TenantObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="TENANT-ID",type="string",JSONPath=".spec.forProvider.tenantId"
+// +kubebuilder:printcolumn:name="ORG-ID",type="string",JSONPath=".status.atProvider.orgId"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,orgmapper}
+
+// A ClusterTenant is a cluster-scoped Tenant, managed by cluster admins. It
+// always uses a ClusterProviderConfig, and shares the org_mapping of its
+// Grafana instance with the Tenants using the same ClusterProviderConfig.
+type ClusterTenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterTenantSpec   `json:"spec"`
+	Status ClusterTenantStatus `json:"status,omitempty"`
+}
+
+// GetParameters returns the parameters of this ClusterTenant.
+func (mg *ClusterTenant) GetParameters() *TenantParameters {
+	return &mg.Spec.ForProvider
+}
+
+// GetObservation returns the observation of this ClusterTenant.
+func (mg *ClusterTenant) GetObservation() *TenantObservation {
+	return &mg.Status.AtProvider
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterTenantList contains a list of ClusterTenant
+type ClusterTenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTenant `json:"items"`
+}
+
+// ClusterTenant type metadata.
+var (
+	ClusterTenantKind             = reflect.TypeOf(ClusterTenant{}).Name()
+	ClusterTenantGroupKind        = schema.GroupKind{Group: Group, Kind: ClusterTenantKind}.String()
+	ClusterTenantKindAPIVersion   = ClusterTenantKind + "." + SchemeGroupVersion.String()
+	ClusterTenantGroupVersionKind = SchemeGroupVersion.WithKind(ClusterTenantKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ClusterTenant{}, &ClusterTenantList{})
+}
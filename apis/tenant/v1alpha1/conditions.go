@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+)
+
+// TypeGrafanaSynced indicates whether the last attempt to sync a Tenant's
+// organization, admins and service accounts to Grafana succeeded.
+const TypeGrafanaSynced xpv1.ConditionType = "GrafanaSynced"
+
+// Reasons a GrafanaSynced condition may be set.
+const (
+	ReasonGrafanaSynced     xpv1.ConditionReason = "Synced"
+	ReasonGrafanaSyncFailed xpv1.ConditionReason = "SyncFailed"
+)
+
+// GrafanaSynced returns a condition indicating that the given generation of
+// the Tenant was synced to Grafana.
+func GrafanaSynced(generation int64) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeGrafanaSynced,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonGrafanaSynced,
+		ObservedGeneration: generation,
+	}
+}
+
+// GrafanaSyncFailed returns a condition indicating that the given generation
+// of the Tenant could not be synced to Grafana.
+func GrafanaSyncFailed(err error, generation int64) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeGrafanaSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonGrafanaSyncFailed,
+		Message:            err.Error(),
+		ObservedGeneration: generation,
+	}
+}
+
+// TypeAdminsProvisioned indicates whether all Tenant admins were granted the
+// Admin role in the Tenant's Grafana organization.
+const TypeAdminsProvisioned xpv1.ConditionType = "AdminsProvisioned"
+
+// Reasons an AdminsProvisioned condition may be set.
+const (
+	ReasonAdminsProvisioned xpv1.ConditionReason = "AdminsProvisioned"
+	ReasonUnresolvedAdmins  xpv1.ConditionReason = "UnresolvedAdmins"
+)
+
+// AdminsProvisioned returns a condition indicating that all admins of the
+// Tenant are Grafana organization admins.
+func AdminsProvisioned() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAdminsProvisioned,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAdminsProvisioned,
+	}
+}
+
+// AdminsUnresolved returns a condition indicating that the given admins could
+// not be resolved to Grafana users.
+func AdminsUnresolved(admins []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAdminsProvisioned,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnresolvedAdmins,
+		Message:            "cannot find Grafana users: " + strings.Join(admins, ", "),
+	}
+}
+
+// TypeOrgMappingSynced indicates whether the org_mapping last synced for the
+// ProviderConfig of a Tenant includes the current generation of the Tenant.
+const TypeOrgMappingSynced xpv1.ConditionType = "OrgMappingSynced"
+
+// Reasons an OrgMappingSynced condition may be set.
+const (
+	ReasonOrgMappingSynced     xpv1.ConditionReason = "Synced"
+	ReasonOrgMappingPending    xpv1.ConditionReason = "Pending"
+	ReasonOrgMappingSyncFailed xpv1.ConditionReason = "SyncFailed"
+	ReasonOrgMappingRemoving   xpv1.ConditionReason = "Removing"
+)
+
+// OrgMappingSynced returns a condition indicating that the given sync
+// generation of the org_mapping included the given generation of the Tenant.
+func OrgMappingSynced(syncGeneration, generation int64) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOrgMappingSynced,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOrgMappingSynced,
+		Message:            fmt.Sprintf("included in org_mapping sync generation %d", syncGeneration),
+		ObservedGeneration: generation,
+	}
+}
+
+// OrgMappingPending returns a condition indicating that the current
+// generation of the Tenant has not been synced to the org_mapping yet.
+func OrgMappingPending() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOrgMappingSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOrgMappingPending,
+		Message:            "waiting for the org_mapping to be synced",
+	}
+}
+
+// OrgMappingRemoving returns a condition indicating that a deleting Tenant
+// waits for its entries to be removed from the org_mapping.
+func OrgMappingRemoving() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOrgMappingSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOrgMappingRemoving,
+		Message:            "waiting for the org_mapping to drop the entries of the tenant",
+	}
+}
+
+// OrgMappingSyncFailed returns a condition indicating that the org_mapping
+// could not be synced since the Tenant last changed.
+func OrgMappingSyncFailed(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOrgMappingSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOrgMappingSyncFailed,
+		Message:            message,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	xpv2 "github.com/crossplane/crossplane-runtime/v2/apis/common/v2"
+)
+
+// GroupSetParameters are the configurable fields of a GroupSet. Group names
+// must be non-empty and must not contain commas, which cannot be represented
+// in org_mapping.
+type GroupSetParameters struct {
+	// ViewerGroups is a list of group claims granted the Viewer role in the
+	// Grafana org of every Tenant referencing this GroupSet.
+	// +kubebuilder:validation:items:Pattern=`^[^,]*[^,\s][^,]*$`
+	// +optional
+	ViewerGroups []string `json:"viewerGroups,omitempty"`
+
+	// EditorGroups is a list of group claims granted the Editor role in the
+	// Grafana org of every Tenant referencing this GroupSet.
+	// +kubebuilder:validation:items:Pattern=`^[^,]*[^,\s][^,]*$`
+	// +optional
+	EditorGroups []string `json:"editorGroups,omitempty"`
+
+	// AdminGroups is a list of group claims granted the Admin role in the
+	// Grafana org of every Tenant referencing this GroupSet.
+	// +kubebuilder:validation:items:Pattern=`^[^,]*[^,\s][^,]*$`
+	// +optional
+	AdminGroups []string `json:"adminGroups,omitempty"`
+}
+
+// GroupSetObservation are the observable fields of a GroupSet.
+type GroupSetObservation struct{}
+
+// A GroupSetSpec defines the desired state of a GroupSet. A GroupSet has no
+// external resource, but it is a managed resource so that the groupSetRefs and
+// groupSetSelector of Tenants can resolve it by its external name. Its
+// providerConfigRef is never used.
+type GroupSetSpec struct {
+	xpv2.ManagedResourceSpec `json:",inline"`
+	ForProvider              GroupSetParameters `json:"forProvider"`
+}
+
+// A GroupSetStatus represents the observed state of a GroupSet.
+type GroupSetStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          GroupSetObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,orgmapper}
+
+// A GroupSet is a reusable bundle of groups per role. Tenants in the same
+// namespace reference it to grant its groups in addition to their own.
+type GroupSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GroupSetSpec   `json:"spec"`
+	Status GroupSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GroupSetList contains a list of GroupSet
+type GroupSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GroupSet `json:"items"`
+}
+
+// GroupSet type metadata.
+var (
+	GroupSetKind             = reflect.TypeOf(GroupSet{}).Name()
+	GroupSetGroupKind        = schema.GroupKind{Group: Group, Kind: GroupSetKind}.String()
+	GroupSetKindAPIVersion   = GroupSetKind + "." + SchemeGroupVersion.String()
+	GroupSetGroupVersionKind = SchemeGroupVersion.WithKind(GroupSetKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&GroupSet{}, &GroupSetList{})
+}
@@ -27,18 +27,54 @@ import (
 )
 
 // TenantParameters are the configurable fields of a Tenant.
+// +kubebuilder:validation:XValidation:rule="(has(self.createOrg) && self.createOrg) ? (has(self.orgName) && !has(self.orgId) && !has(self.orgIdRef) && !has(self.orgIdSelector)) : (has(self.orgId) || has(self.orgIdRef) || has(self.orgIdSelector))",message="orgId, orgIdRef or orgIdSelector is required unless createOrg is true, in which case orgName is required and none of them may be set"
 type TenantParameters struct {
-	// TenantID is the unique identifier for this tenant.
+	// TenantID is the unique identifier for this tenant. It is immutable.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tenantId is immutable"
 	TenantID string `json:"tenantId"`
 
-	// OrgID is the mapped organization identifier.
-	// +kubebuilder:validation:Required
+	// OrgID is the mapped organization identifier. It is required unless
+	// CreateOrg is true, in which case the ID of the created organization is
+	// reported in status.atProvider.orgId. Changing it moves the tenant to
+	// another organization: its org_mapping entries and admins are removed
+	// from the previous organization and a MovedOrg event is emitted.
 	// +kubebuilder:validation:MinLength=1
-	OrgID string `json:"orgId"`
+	// +optional
+	OrgID string `json:"orgId,omitempty"`
+
+	// OrgIDRef references a managed resource, such as a Grafana Organization
+	// of provider-grafana, to set OrgID from.
+	// +optional
+	OrgIDRef *TypedReference `json:"orgIdRef,omitempty"`
+
+	// OrgIDSelector selects a managed resource to set OrgIDRef and OrgID
+	// from.
+	// +optional
+	OrgIDSelector *TypedSelector `json:"orgIdSelector,omitempty"`
+
+	// OrgName is the name of the Grafana organization to create and manage
+	// when CreateOrg is true.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	OrgName string `json:"orgName,omitempty"`
 
-	// Admins is a list of tenant administrators (typically GitHub IDs).
+	// CreateOrg makes the provider create the Grafana organization named
+	// OrgName if it does not exist, and keep its name in sync.
+	// +optional
+	CreateOrg bool `json:"createOrg,omitempty"`
+
+	// OrgDeletionPolicy determines whether the Grafana organization managed
+	// through CreateOrg is deleted when the Tenant is deleted.
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	// +kubebuilder:default=Orphan
+	// +optional
+	OrgDeletionPolicy xpv1.DeletionPolicy `json:"orgDeletionPolicy,omitempty"`
+
+	// Admins is a list of tenant administrators, identified by Grafana login
+	// or email. Each admin is granted the Admin role in the tenant's Grafana
+	// org, and removed from the org when dropped from this list.
 	// +optional
 	Admins []string `json:"admins,omitempty"`
 
@@ -54,11 +90,133 @@ type TenantParameters struct {
 	// +optional
 	AdminGroups []string `json:"adminGroups,omitempty"`
 
+	// AdminGroupsRefs reference managed resources, such as groups of an
+	// identity provider, to set AdminGroups from. The resolved groups
+	// replace those listed in AdminGroups.
+	// +optional
+	AdminGroupsRefs []TypedReference `json:"adminGroupsRefs,omitempty"`
+
+	// AdminGroupsSelector selects managed resources to set AdminGroupsRefs
+	// and AdminGroups from.
+	// +optional
+	AdminGroupsSelector *TypedSelector `json:"adminGroupsSelector,omitempty"`
+
+	// GroupSets are the names of GroupSets in the Tenant's namespace whose
+	// groups are granted in addition to ViewerGroups, EditorGroups and
+	// AdminGroups. Changes to a GroupSet are synced to every Tenant
+	// referencing it.
+	// +crossplane:generate:reference:type=GroupSet
+	// +crossplane:generate:reference:refFieldName=GroupSetRefs
+	// +crossplane:generate:reference:selectorFieldName=GroupSetSelector
+	// +optional
+	GroupSets []string `json:"groupSets,omitempty"`
+
+	// GroupSetRefs are references to GroupSets used to set GroupSets.
+	// +optional
+	GroupSetRefs []xpv1.NamespacedReference `json:"groupSetRefs,omitempty"`
+
+	// GroupSetSelector selects references to GroupSets used to set
+	// GroupSets.
+	// +optional
+	GroupSetSelector *xpv1.NamespacedSelector `json:"groupSetSelector,omitempty"`
+
+	// ConnectionServiceAccount makes the provider create a Grafana service
+	// account in the tenant's org and publish a token for it to the
+	// connection secret. It requires writeConnectionSecretToRef.
+	// +optional
+	ConnectionServiceAccount *ConnectionServiceAccount `json:"connectionServiceAccount,omitempty"`
+
+	// ServiceAccounts are Grafana service accounts created in the tenant's
+	// org for machine access. The token of each is published to the
+	// connection secret under token.<name> and rotated before it expires.
+	// Service accounts removed from this list are deleted. They require
+	// writeConnectionSecretToRef.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ServiceAccounts []ServiceAccount `json:"serviceAccounts,omitempty"`
+
 	// Retention defines data retention settings for each signal type.
 	// +kubebuilder:validation:Required
 	Retention RetentionPolicy `json:"retention"`
 }
 
+// A ConnectionServiceAccount is a Grafana service account whose token is
+// published to the connection secret of a Tenant.
+type ConnectionServiceAccount struct {
+	// Name of the service account. Defaults to orgmapper-<tenantId>. An
+	// existing service account with this name is adopted.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Role of the service account in the tenant's Grafana org.
+	// +kubebuilder:validation:Enum=Viewer;Editor;Admin
+	// +kubebuilder:default=Viewer
+	// +optional
+	Role string `json:"role,omitempty"`
+}
+
+// A TypedReference references a managed resource of any kind by name. Unlike
+// references to GroupSets, it names the kind of the referenced resource, which
+// may be served by another provider.
+type TypedReference struct {
+	// APIVersion of the referenced resource.
+	// +kubebuilder:validation:MinLength=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referenced resource.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// FieldPath of the value to use, such as status.atProvider.orgId.
+	// Defaults to the external name of the referenced resource.
+	// +optional
+	FieldPath string `json:"fieldPath,omitempty"`
+
+	xpv1.NamespacedReference `json:",inline"`
+}
+
+// A TypedSelector selects managed resources of any kind by label.
+type TypedSelector struct {
+	// APIVersion of the selected resources.
+	// +kubebuilder:validation:MinLength=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the selected resources.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// FieldPath of the value to use, such as status.atProvider.orgId.
+	// Defaults to the external name of the selected resources.
+	// +optional
+	FieldPath string `json:"fieldPath,omitempty"`
+
+	xpv1.NamespacedSelector `json:",inline"`
+}
+
+// A ServiceAccount is a Grafana service account created for a Tenant.
+type ServiceAccount struct {
+	// Name of the service account. An existing service account with this
+	// name is adopted. It must differ from the name of the connection
+	// service account.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._-]+$`
+	Name string `json:"name"`
+
+	// Role of the service account in the tenant's Grafana org.
+	// +kubebuilder:validation:Enum=Viewer;Editor;Admin
+	// +kubebuilder:default=Viewer
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// TokenTTL is how long each token of the service account is valid, such
+	// as 720h. A new token is published when less than a third of it
+	// remains; the previous token stays valid until it expires. Tokens
+	// never expire when unset.
+	// +optional
+	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
+}
+
 // RetentionPolicy defines data retention durations for each signal type.
 type RetentionPolicy struct {
 	// Logs retention duration (e.g. "30d", "24h", "1w").
@@ -86,15 +244,43 @@ type RetentionPolicy struct {
 type TenantObservation struct {
 	TenantID     string          `json:"tenantId,omitempty"`
 	OrgID        string          `json:"orgId,omitempty"`
+	OrgName      string          `json:"orgName,omitempty"`
 	Admins       []string        `json:"admins,omitempty"`
 	ViewerGroups []string        `json:"viewerGroups,omitempty"`
 	EditorGroups []string        `json:"editorGroups,omitempty"`
 	AdminGroups  []string        `json:"adminGroups,omitempty"`
 	Retention    RetentionPolicy `json:"retention,omitempty"`
 	LastUpdated  string          `json:"lastUpdated,omitempty"`
+
+	// LastSyncAttempt is when the Tenant was last synced to Grafana,
+	// successfully or not. The outcome is reported by the GrafanaSynced
+	// condition.
+	LastSyncAttempt *metav1.Time `json:"lastSyncAttempt,omitempty"`
+
+	// ConnectionServiceAccount is the service account whose token is
+	// published to the connection secret.
+	ConnectionServiceAccount *ServiceAccountObservation `json:"connectionServiceAccount,omitempty"`
+
+	// ServiceAccounts are the service accounts created for
+	// spec.forProvider.serviceAccounts.
+	ServiceAccounts []ServiceAccountObservation `json:"serviceAccounts,omitempty"`
+}
+
+// ServiceAccountObservation identifies a Grafana service account managed for
+// a Tenant.
+type ServiceAccountObservation struct {
+	ID    int64  `json:"id,omitempty"`
+	OrgID string `json:"orgId,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Role  string `json:"role,omitempty"`
+
+	// TokenExpiresAt is when the published token expires. It is unset for
+	// tokens that never expire.
+	TokenExpiresAt *metav1.Time `json:"tokenExpiresAt,omitempty"`
 }
 
 // A TenantSpec defines the desired state of a Tenant.
+// +kubebuilder:validation:XValidation:rule="(!has(self.forProvider.connectionServiceAccount) && !has(self.forProvider.serviceAccounts)) || has(self.writeConnectionSecretToRef)",message="connectionServiceAccount and serviceAccounts require writeConnectionSecretToRef"
 type TenantSpec struct {
 	xpv2.ManagedResourceSpec `json:",inline"`
 	ForProvider              TenantParameters `json:"forProvider"`
@@ -112,7 +298,7 @@ type TenantStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="TENANT-ID",type="string",JSONPath=".spec.forProvider.tenantId"
-// +kubebuilder:printcolumn:name="ORG-ID",type="string",JSONPath=".spec.forProvider.orgId"
+// +kubebuilder:printcolumn:name="ORG-ID",type="string",JSONPath=".status.atProvider.orgId"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,orgmapper}
 
@@ -125,6 +311,16 @@ type Tenant struct {
 	Status TenantStatus `json:"status,omitempty"`
 }
 
+// GetParameters returns the parameters of this Tenant.
+func (mg *Tenant) GetParameters() *TenantParameters {
+	return &mg.Spec.ForProvider
+}
+
+// GetObservation returns the observation of this Tenant.
+func (mg *Tenant) GetObservation() *TenantObservation {
+	return &mg.Status.AtProvider
+}
+
 // +kubebuilder:object:root=true
 
 // TenantList contains a list of Tenant
@@ -134,6 +330,11 @@ type TenantList struct {
 	Items           []Tenant `json:"items"`
 }
 
+// AnnotationKeyForceDelete lets a deleting Tenant or ClusterTenant go
+// without confirming that its entries were removed from the org_mapping, e.g.
+// when its Grafana instance is gone for good. The value must be "true".
+const AnnotationKeyForceDelete = "orgmapper.crossplane.io/force-delete"
+
 // Tenant type metadata.
 var (
 	TenantKind             = reflect.TypeOf(Tenant{}).Name()
@@ -9,9 +9,257 @@
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTenant) DeepCopyInto(out *ClusterTenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTenant.
+func (in *ClusterTenant) DeepCopy() *ClusterTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTenantList) DeepCopyInto(out *ClusterTenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTenantList.
+func (in *ClusterTenantList) DeepCopy() *ClusterTenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTenantSpec) DeepCopyInto(out *ClusterTenantSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTenantSpec.
+func (in *ClusterTenantSpec) DeepCopy() *ClusterTenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTenantStatus) DeepCopyInto(out *ClusterTenantStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTenantStatus.
+func (in *ClusterTenantStatus) DeepCopy() *ClusterTenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionServiceAccount) DeepCopyInto(out *ConnectionServiceAccount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionServiceAccount.
+func (in *ConnectionServiceAccount) DeepCopy() *ConnectionServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSet) DeepCopyInto(out *GroupSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSet.
+func (in *GroupSet) DeepCopy() *GroupSet {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSetList) DeepCopyInto(out *GroupSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GroupSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSetList.
+func (in *GroupSetList) DeepCopy() *GroupSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSetObservation) DeepCopyInto(out *GroupSetObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSetObservation.
+func (in *GroupSetObservation) DeepCopy() *GroupSetObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSetObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSetParameters) DeepCopyInto(out *GroupSetParameters) {
+	*out = *in
+	if in.ViewerGroups != nil {
+		in, out := &in.ViewerGroups, &out.ViewerGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EditorGroups != nil {
+		in, out := &in.EditorGroups, &out.EditorGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdminGroups != nil {
+		in, out := &in.AdminGroups, &out.AdminGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSetParameters.
+func (in *GroupSetParameters) DeepCopy() *GroupSetParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSetParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSetSpec) DeepCopyInto(out *GroupSetSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSetSpec.
+func (in *GroupSetSpec) DeepCopy() *GroupSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSetStatus) DeepCopyInto(out *GroupSetStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	out.AtProvider = in.AtProvider
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSetStatus.
+func (in *GroupSetStatus) DeepCopy() *GroupSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetentionPolicy) DeepCopyInto(out *RetentionPolicy) {
 	*out = *in
@@ -27,6 +275,45 @@ func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccount) DeepCopyInto(out *ServiceAccount) {
+	*out = *in
+	if in.TokenTTL != nil {
+		in, out := &in.TokenTTL, &out.TokenTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccount.
+func (in *ServiceAccount) DeepCopy() *ServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountObservation) DeepCopyInto(out *ServiceAccountObservation) {
+	*out = *in
+	if in.TokenExpiresAt != nil {
+		in, out := &in.TokenExpiresAt, &out.TokenExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountObservation.
+func (in *ServiceAccountObservation) DeepCopy() *ServiceAccountObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tenant) DeepCopyInto(out *Tenant) {
 	*out = *in
@@ -110,6 +397,22 @@ func (in *TenantObservation) DeepCopyInto(out *TenantObservation) {
 		copy(*out, *in)
 	}
 	out.Retention = in.Retention
+	if in.LastSyncAttempt != nil {
+		in, out := &in.LastSyncAttempt, &out.LastSyncAttempt
+		*out = (*in).DeepCopy()
+	}
+	if in.ConnectionServiceAccount != nil {
+		in, out := &in.ConnectionServiceAccount, &out.ConnectionServiceAccount
+		*out = new(ServiceAccountObservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]ServiceAccountObservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantObservation.
@@ -125,6 +428,16 @@ func (in *TenantObservation) DeepCopy() *TenantObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantParameters) DeepCopyInto(out *TenantParameters) {
 	*out = *in
+	if in.OrgIDRef != nil {
+		in, out := &in.OrgIDRef, &out.OrgIDRef
+		*out = new(TypedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OrgIDSelector != nil {
+		in, out := &in.OrgIDSelector, &out.OrgIDSelector
+		*out = new(TypedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Admins != nil {
 		in, out := &in.Admins, &out.Admins
 		*out = make([]string, len(*in))
@@ -145,6 +458,47 @@ func (in *TenantParameters) DeepCopyInto(out *TenantParameters) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AdminGroupsRefs != nil {
+		in, out := &in.AdminGroupsRefs, &out.AdminGroupsRefs
+		*out = make([]TypedReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdminGroupsSelector != nil {
+		in, out := &in.AdminGroupsSelector, &out.AdminGroupsSelector
+		*out = new(TypedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GroupSets != nil {
+		in, out := &in.GroupSets, &out.GroupSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupSetRefs != nil {
+		in, out := &in.GroupSetRefs, &out.GroupSetRefs
+		*out = make([]v1.NamespacedReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GroupSetSelector != nil {
+		in, out := &in.GroupSetSelector, &out.GroupSetSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionServiceAccount != nil {
+		in, out := &in.ConnectionServiceAccount, &out.ConnectionServiceAccount
+		*out = new(ConnectionServiceAccount)
+		**out = **in
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]ServiceAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.Retention = in.Retention
 }
 
@@ -191,3 +545,35 @@ func (in *TenantStatus) DeepCopy() *TenantStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypedReference) DeepCopyInto(out *TypedReference) {
+	*out = *in
+	in.NamespacedReference.DeepCopyInto(&out.NamespacedReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypedReference.
+func (in *TypedReference) DeepCopy() *TypedReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TypedReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypedSelector) DeepCopyInto(out *TypedSelector) {
+	*out = *in
+	in.NamespacedSelector.DeepCopyInto(&out.NamespacedSelector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypedSelector.
+func (in *TypedSelector) DeepCopy() *TypedSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(TypedSelector)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+)
+
+// TypeOrgMappingEffective indicates whether Grafana applies the org_mapping
+// written to the SSO providers of a ProviderConfig. Grafana ignores
+// org_mapping unless orgAttributePath selects the claim carrying the groups.
+const TypeOrgMappingEffective xpv1.ConditionType = "OrgMappingEffective"
+
+// Reasons an OrgMappingEffective condition may be set.
+const (
+	ReasonOrgAttributePathSet     xpv1.ConditionReason = "OrgAttributePathSet"
+	ReasonOrgAttributePathMissing xpv1.ConditionReason = "OrgAttributePathMissing"
+)
+
+// OrgMappingEffective returns a condition indicating that every SSO provider
+// has an orgAttributePath.
+func OrgMappingEffective() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOrgMappingEffective,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOrgAttributePathSet,
+	}
+}
+
+// OrgAttributePathMissing returns a condition indicating that the given SSO
+// providers have no orgAttributePath, so Grafana ignores their org_mapping.
+func OrgAttributePathMissing(providers []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOrgMappingEffective,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOrgAttributePathMissing,
+		Message: "Grafana ignores the org_mapping of SSO providers without an orgAttributePath: " + strings.Join(providers, ", ") +
+			"; set spec.ssoSettings.orgAttributePath to the claim carrying the groups, e.g. groups",
+	}
+}
+
+// TypeOrgMappingSynced indicates whether the org_mapping of the Tenants using
+// a ProviderConfig was last synced to Grafana successfully.
+const TypeOrgMappingSynced xpv1.ConditionType = "OrgMappingSynced"
+
+// Reasons an OrgMappingSynced condition may be set.
+const (
+	ReasonOrgMappingSynced     xpv1.ConditionReason = "Synced"
+	ReasonOrgMappingSyncFailed xpv1.ConditionReason = "SyncFailed"
+)
+
+// OrgMappingSynced returns a condition indicating that every SSO provider
+// holds the org_mapping of the Tenants.
+func OrgMappingSynced() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOrgMappingSynced,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOrgMappingSynced,
+	}
+}
+
+// OrgMappingSyncFailed returns a condition indicating that the org_mapping
+// of the Tenants could not be synced.
+func OrgMappingSyncFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOrgMappingSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOrgMappingSyncFailed,
+		Message:            err.Error(),
+	}
+}
@@ -53,9 +53,23 @@ var (
 	ClusterProviderConfigUsageListGroupVersionKind = SchemeGroupVersion.WithKind(ClusterProviderConfigUsageListKind)
 )
 
+// OrgMapping type metadata.
+var (
+	OrgMappingKind             = reflect.TypeOf(OrgMapping{}).Name()
+	OrgMappingGroupVersionKind = SchemeGroupVersion.WithKind(OrgMappingKind)
+)
+
+// ClusterOrgMapping type metadata.
+var (
+	ClusterOrgMappingKind             = reflect.TypeOf(ClusterOrgMapping{}).Name()
+	ClusterOrgMappingGroupVersionKind = SchemeGroupVersion.WithKind(ClusterOrgMappingKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&ProviderConfig{}, &ProviderConfigList{})
 	SchemeBuilder.Register(&ProviderConfigUsage{}, &ProviderConfigUsageList{})
 	SchemeBuilder.Register(&ClusterProviderConfig{}, &ClusterProviderConfigList{})
 	SchemeBuilder.Register(&ClusterProviderConfigUsage{}, &ClusterProviderConfigUsageList{})
+	SchemeBuilder.Register(&OrgMapping{}, &OrgMappingList{})
+	SchemeBuilder.Register(&ClusterOrgMapping{}, &ClusterOrgMappingList{})
 }
@@ -9,6 +9,241 @@ import (
 // A ProviderConfigStatus defines the status of a Provider.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// ManagedOrgMapping lists the org_mapping entries last written to Grafana
+	// by this provider. In Owned mode only these entries are ever removed.
+	// +optional
+	ManagedOrgMapping []string `json:"managedOrgMapping,omitempty"`
+
+	// OrgMappingSync records the last successful sync of the org_mapping of
+	// the Tenants and ClusterTenants using this config.
+	// +optional
+	OrgMappingSync *OrgMappingSync `json:"orgMappingSync,omitempty"`
+
+	// GrafanaVersion is the version reported by Grafana when it was last
+	// probed.
+	// +optional
+	GrafanaVersion string `json:"grafanaVersion,omitempty"`
+}
+
+// OrgMappingSync records a successful sync of the org_mapping of all Tenants
+// and ClusterTenants using a config.
+type OrgMappingSync struct {
+	// Generation is incremented by every sync that writes to Grafana or
+	// includes a different set of Tenants.
+	Generation int64 `json:"generation"`
+
+	// LastSyncTime is when Generation was last incremented.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Tenants maps the UID of every Tenant and ClusterTenant included in the
+	// sync to its metadata.generation at the time.
+	// +optional
+	Tenants map[string]int64 `json:"tenants,omitempty"`
+}
+
+// OrgMappingMode determines how the provider treats org_mapping entries it
+// did not generate.
+type OrgMappingMode string
+
+// Supported org_mapping modes.
+const (
+	// OrgMappingModeAuthoritative replaces the whole org_mapping with the
+	// entries computed from Tenants.
+	OrgMappingModeAuthoritative OrgMappingMode = "Authoritative"
+
+	// OrgMappingModeOwned only adds and removes the entries computed from
+	// Tenants, leaving manually configured entries untouched.
+	OrgMappingModeOwned OrgMappingMode = "Owned"
+)
+
+// SSOProvider is the key of a Grafana SSO provider that supports org_mapping.
+// +kubebuilder:validation:Enum=generic_oauth;azuread;okta;github;gitlab;google
+type SSOProvider string
+
+// Supported SSO providers.
+const (
+	SSOProviderGenericOAuth SSOProvider = "generic_oauth"
+	SSOProviderAzureAD      SSOProvider = "azuread"
+	SSOProviderOkta         SSOProvider = "okta"
+	SSOProviderGitHub       SSOProvider = "github"
+	SSOProviderGitLab       SSOProvider = "gitlab"
+	SSOProviderGoogle       SSOProvider = "google"
+)
+
+// SSOSettings are SSO provider settings managed together with org_mapping.
+// Only the fields that are set are written; any other setting is left as
+// configured in Grafana. Drift of the set fields is corrected.
+type SSOSettings struct {
+	// RoleAttributePath is a JMESPath expression that maps claims to the
+	// Grafana role of a user.
+	// +optional
+	RoleAttributePath *string `json:"roleAttributePath,omitempty"`
+
+	// RoleAttributeStrict denies sign-in when no role can be derived from
+	// RoleAttributePath.
+	// +optional
+	RoleAttributeStrict *bool `json:"roleAttributeStrict,omitempty"`
+
+	// OrgAttributePath is a JMESPath expression that selects the claim
+	// org_mapping is matched against.
+	// +optional
+	OrgAttributePath *string `json:"orgAttributePath,omitempty"`
+
+	// GroupsAttributePath is a JMESPath expression that selects the groups
+	// of a user.
+	// +optional
+	GroupsAttributePath *string `json:"groupsAttributePath,omitempty"`
+
+	// AllowAssignGrafanaAdmin lets RoleAttributePath grant the GrafanaAdmin
+	// server role.
+	// +optional
+	AllowAssignGrafanaAdmin *bool `json:"allowAssignGrafanaAdmin,omitempty"`
+
+	// SkipOrgRoleSync stops Grafana from syncing organization roles from
+	// the SSO provider on sign-in.
+	// +optional
+	SkipOrgRoleSync *bool `json:"skipOrgRoleSync,omitempty"`
+}
+
+// RetentionOverridesKind is the kind of object retention overrides are
+// written to.
+type RetentionOverridesKind string
+
+// Supported retention overrides kinds.
+const (
+	RetentionOverridesKindConfigMap RetentionOverridesKind = "ConfigMap"
+	RetentionOverridesKindSecret    RetentionOverridesKind = "Secret"
+)
+
+// RetentionOverrides names the ConfigMap or Secret that per-tenant retention
+// runtime overrides are written to. The object holds one overrides file per
+// backend: loki.yaml, mimir.yaml, tempo.yaml and pyroscope.yaml.
+type RetentionOverrides struct {
+	// Kind of the object the overrides are written to.
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	// +kubebuilder:default=ConfigMap
+	// +optional
+	Kind RetentionOverridesKind `json:"kind,omitempty"`
+
+	// Name of the object the overrides are written to.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the object the overrides are written to. Defaults to the
+	// namespace of a ProviderConfig, and is required for a
+	// ClusterProviderConfig.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// TLSConfig configures TLS for connections to Grafana.
+type TLSConfig struct {
+	// CABundle is a PEM-encoded bundle of CA certificates trusted, in
+	// addition to the system roots, to verify Grafana's serving certificate.
+	// +optional
+	CABundle *CABundleSource `json:"caBundle,omitempty"`
+
+	// ClientCertSecretRef references a kubernetes.io/tls Secret whose tls.crt
+	// and tls.key are presented to Grafana as a client certificate.
+	// +optional
+	ClientCertSecretRef *xpv1.SecretReference `json:"clientCertSecretRef,omitempty"`
+
+	// InsecureSkipVerify disables verification of Grafana's serving
+	// certificate. It must only be used in development.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// CABundleSource selects the key of a Secret or ConfigMap that holds a CA
+// bundle.
+// +kubebuilder:validation:XValidation:rule="has(self.secretRef) != has(self.configMapRef)",message="exactly one of secretRef and configMapRef must be set"
+type CABundleSource struct {
+	// SecretRef selects a key of a Secret.
+	// +optional
+	SecretRef *xpv1.SecretKeySelector `json:"secretRef,omitempty"`
+
+	// ConfigMapRef selects a key of a ConfigMap.
+	// +optional
+	ConfigMapRef *ConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+// ConfigMapKeySelector selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Key of the ConfigMap to select.
+	Key string `json:"key"`
+}
+
+// HTTPConfig configures requests to Grafana.
+type HTTPConfig struct {
+	// Timeout bounds each Grafana API call, including its retries. Calls are
+	// not bounded when unset.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Retries is the number of times a call that fails with a connection
+	// error, a 429 or a 5xx response is retried.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retries int `json:"retries,omitempty"`
+
+	// RetryWait is the time to wait before each retry. Retries back off
+	// exponentially from 2s when unset.
+	// +optional
+	RetryWait *metav1.Duration `json:"retryWait,omitempty"`
+
+	// Headers are added to every request, e.g. X-Grafana-Org-Id.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// AuthMode is how the provider authenticates to Grafana.
+type AuthMode string
+
+// Supported auth modes.
+const (
+	AuthModeServiceAccountToken     AuthMode = "ServiceAccountToken"
+	AuthModeBasicAuth               AuthMode = "BasicAuth"
+	AuthModeOAuth2ClientCredentials AuthMode = "OAuth2ClientCredentials"
+)
+
+// AuthConfig selects how the credentials are presented to Grafana.
+// +kubebuilder:validation:XValidation:rule="self.mode != 'OAuth2ClientCredentials' || has(self.oauth2)",message="oauth2 is required when mode is OAuth2ClientCredentials"
+type AuthConfig struct {
+	// Mode determines the format of the credentials. ServiceAccountToken
+	// expects a token, BasicAuth a JSON object with "username" and
+	// "password", and OAuth2ClientCredentials a JSON object with "clientId"
+	// and "clientSecret" that are exchanged for a bearer token.
+	// +kubebuilder:validation:Enum=ServiceAccountToken;BasicAuth;OAuth2ClientCredentials
+	Mode AuthMode `json:"mode"`
+
+	// OAuth2 configures the client credentials flow, e.g. to reach a
+	// Grafana behind an identity-aware proxy.
+	// +optional
+	OAuth2 *OAuth2Config `json:"oauth2,omitempty"`
+}
+
+// OAuth2Config configures the OAuth2 client credentials flow.
+type OAuth2Config struct {
+	// TokenURL is the token endpoint of the authorization server.
+	// +kubebuilder:validation:MinLength=1
+	TokenURL string `json:"tokenUrl"`
+
+	// Scopes requested for the token.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// EndpointParams are additional parameters, such as audience, sent to
+	// the token endpoint.
+	// +optional
+	EndpointParams map[string]string `json:"endpointParams,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -30,12 +265,54 @@ type ProviderConfigSpec struct {
 	// Use source: Secret with a secretRef for a service account token (single key)
 	// or basic auth credentials (JSON with "username" and "password" keys).
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// Auth selects how the credentials are presented to Grafana. When unset,
+	// credentials that are a JSON object are used for basic auth and any
+	// other credentials as a service account token.
+	// +optional
+	Auth *AuthConfig `json:"auth,omitempty"`
+
+	// TLS configures how the Grafana instance is verified and how the
+	// provider authenticates to it at the transport level.
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// HTTP configures timeouts and retries of Grafana API calls.
+	// +optional
+	HTTP *HTTPConfig `json:"http,omitempty"`
+
+	// OrgMappingMode controls whether the provider owns the whole org_mapping
+	// (Authoritative) or only the entries it generated (Owned).
+	// +kubebuilder:validation:Enum=Authoritative;Owned
+	// +kubebuilder:default=Authoritative
+	// +optional
+	OrgMappingMode OrgMappingMode `json:"orgMappingMode,omitempty"`
+
+	// SSOProviders are the Grafana SSO providers the org_mapping is written
+	// to. Every provider receives the same mapping.
+	// +kubebuilder:default={"generic_oauth"}
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +optional
+	SSOProviders []SSOProvider `json:"ssoProviders,omitempty"`
+
+	// SSOSettings are written to every SSO provider alongside org_mapping.
+	// +optional
+	SSOSettings *SSOSettings `json:"ssoSettings,omitempty"`
+
+	// RetentionOverrides is the object the retention policies of all Tenants
+	// using this config are rendered to, in the runtime overrides format of
+	// Loki, Mimir, Tempo and Pyroscope. Retention is not enforced when unset.
+	// +optional
+	RetentionOverrides *RetentionOverrides `json:"retentionOverrides,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.grafanaVersion"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,provider,orgmapper}
@@ -85,6 +362,8 @@ type ProviderConfigUsageList struct {
 // +kubebuilder:object:root=true
 
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.grafanaVersion"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,orgmapper}
@@ -130,3 +409,124 @@ type ClusterProviderConfigUsageList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []ClusterProviderConfigUsage `json:"items"`
 }
+
+// OrgMappingStatus is the org_mapping the provider last rendered for a
+// ProviderConfig or ClusterProviderConfig, and what it found in Grafana.
+type OrgMappingStatus struct {
+	// SyncGeneration is the generation of the last successful sync, as
+	// recorded in status.orgMappingSync of the config.
+	// +optional
+	SyncGeneration int64 `json:"syncGeneration,omitempty"`
+
+	// LastSyncTime is when the last successful sync wrote to Grafana or
+	// included a different set of Tenants.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastError is the error of the last sync, empty if it succeeded.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// Entries are the org_mapping entries rendered from Tenants.
+	// +optional
+	Entries []string `json:"entries,omitempty"`
+
+	// Tenants are the Tenants and ClusterTenants that contributed entries.
+	// +optional
+	Tenants []OrgMappingTenant `json:"tenants,omitempty"`
+
+	// Providers is the org_mapping found in each SSO provider.
+	// +optional
+	Providers []OrgMappingProvider `json:"providers,omitempty"`
+}
+
+// OrgMappingTenant is a Tenant or ClusterTenant contributing to an
+// org_mapping.
+type OrgMappingTenant struct {
+	// Kind of the Tenant, i.e. Tenant or ClusterTenant.
+	Kind string `json:"kind"`
+
+	// Namespace of the Tenant, empty for a ClusterTenant.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the Tenant.
+	Name string `json:"name"`
+
+	// TenantID of the Tenant.
+	TenantID string `json:"tenantId"`
+
+	// OrgID is the Grafana organization the Tenant's groups are mapped to.
+	OrgID string `json:"orgId"`
+}
+
+// OrgMappingProvider is the org_mapping of an SSO provider after the last
+// sync.
+type OrgMappingProvider struct {
+	// Name of the SSO provider.
+	Name string `json:"name"`
+
+	// Hash is the SHA-256 of the org_mapping Grafana holds.
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// ForeignEntries are entries found in Grafana that were not rendered
+	// from Tenants. They are removed in Authoritative mode and kept in Owned
+	// mode.
+	// +optional
+	ForeignEntries []string `json:"foreignEntries,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="GENERATION",type="integer",JSONPath=".status.syncGeneration"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="ERROR",type="string",JSONPath=".status.lastError",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,provider,orgmapper}
+
+// An OrgMapping shows the org_mapping the provider writes for the
+// ProviderConfig of the same name. It is generated and kept up to date by the
+// provider; changes to it are overwritten.
+type OrgMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status OrgMappingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OrgMappingList contains a list of OrgMapping.
+type OrgMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OrgMapping `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="GENERATION",type="integer",JSONPath=".status.syncGeneration"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="ERROR",type="string",JSONPath=".status.lastError",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,orgmapper}
+
+// A ClusterOrgMapping shows the org_mapping the provider writes for the
+// ClusterProviderConfig of the same name. It is generated and kept up to date
+// by the provider; changes to it are overwritten.
+type ClusterOrgMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status OrgMappingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterOrgMappingList contains a list of ClusterOrgMapping.
+type ClusterOrgMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterOrgMapping `json:"items"`
+}
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	changelogsv1alpha1 "github.com/crossplane/crossplane-runtime/v2/apis/changelogs/proto/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
@@ -57,9 +58,14 @@ func main() {
 
 		maxReconcileRate = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
 
+		orgMappingDebounce = app.Flag("org-mapping-debounce", "How long the Tenants of a Grafana instance must stay unchanged before its org_mapping is written.").Default("5s").Envar("ORG_MAPPING_DEBOUNCE").Duration()
+
 		enableManagementPolicies = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("true").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
 		enableChangeLogs         = app.Flag("enable-changelogs", "Enable support for capturing change logs during reconciliation.").Default("false").Envar("ENABLE_CHANGE_LOGS").Bool()
 		changelogsSocketPath     = app.Flag("changelogs-socket-path", "Path for changelogs socket (if enabled)").Default("/var/run/changelogs/changelogs.sock").Envar("CHANGELOGS_SOCKET_PATH").String()
+
+		enableWebhooks = app.Flag("enable-webhooks", "Enable support for admission webhooks.").Default("true").Envar("ENABLE_WEBHOOKS").Bool()
+		certsDir       = app.Flag("certs-dir", "The directory that contains the webhook server key and certificate.").Default("/tls/server").Envar("TLS_SERVER_CERTS_DIR").String()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -98,6 +104,12 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		// Crossplane provisions the webhook serving certificate of provider
+		// packages and mounts it at TLS_SERVER_CERTS_DIR.
+		WebhookServer: webhook.NewServer(webhook.Options{
+			CertDir: *certsDir,
+		}),
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
@@ -142,6 +154,9 @@ func main() {
 		o.ChangeLogOptions = &clo
 	}
 
-	kingpin.FatalIfError(orgmapper.Setup(mgr, o), "Cannot setup OrgMapper controllers")
+	kingpin.FatalIfError(orgmapper.Setup(mgr, o, *orgMappingDebounce), "Cannot setup OrgMapper controllers")
+	if *enableWebhooks {
+		kingpin.FatalIfError(orgmapper.SetupWebhooks(mgr), "Cannot setup OrgMapper webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
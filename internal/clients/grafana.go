@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clients builds Grafana API clients from ProviderConfigs.
+package clients
+
+import (
+	"context"
+	"time"
+
+	goapi "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	apisv1alpha1 "github.com/loafoe/provider-orgmapper/apis/v1alpha1"
+	"github.com/loafoe/provider-orgmapper/internal/grafana"
+)
+
+const (
+	errGetCreds      = "cannot get credentials"
+	errNewClient     = "cannot create Grafana client"
+	errGetCABundle   = "cannot get CA bundle"
+	errGetClientCert = "cannot get client certificate"
+)
+
+// NewGrafanaClient creates a Grafana client for the given ProviderConfig spec,
+// reading its credentials and TLS material from the API server.
+func NewGrafanaClient(ctx context.Context, kube client.Client, spec *apisv1alpha1.ProviderConfigSpec) (*goapi.GrafanaHTTPAPI, error) {
+	creds, err := resource.CommonCredentialExtractor(ctx, spec.Credentials.Source, kube, spec.Credentials.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	opts, err := ClientOptions(ctx, kube, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	gClient, err := grafana.NewClient(spec.GrafanaURL, creds, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return gClient, nil
+}
+
+// SSOProviders returns the keys of the SSO providers a ProviderConfig writes
+// the org_mapping to.
+func SSOProviders(spec *apisv1alpha1.ProviderConfigSpec) []string {
+	if len(spec.SSOProviders) == 0 {
+		return []string{grafana.DefaultSSOProvider}
+	}
+	providers := make([]string, 0, len(spec.SSOProviders))
+	for _, p := range spec.SSOProviders {
+		providers = append(providers, string(p))
+	}
+	return providers
+}
+
+// ClientOptions resolves the auth, TLS and HTTP settings of a ProviderConfig into
+// Grafana client options, reading CA bundles and client certificates from the
+// referenced Secrets and ConfigMaps.
+func ClientOptions(ctx context.Context, kube client.Client, spec *apisv1alpha1.ProviderConfigSpec) ([]grafana.ClientOption, error) {
+	var opts []grafana.ClientOption
+
+	if a := spec.Auth; a != nil {
+		opts = append(opts, grafana.WithAuthMode(grafana.AuthMode(a.Mode)))
+		if o := a.OAuth2; o != nil {
+			opts = append(opts, grafana.WithOAuth2(o.TokenURL, o.Scopes, o.EndpointParams))
+		}
+	}
+
+	if t := spec.TLS; t != nil {
+		if t.InsecureSkipVerify {
+			opts = append(opts, grafana.WithInsecureSkipVerify())
+		}
+		if b := t.CABundle; b != nil {
+			var bundle []byte
+			switch {
+			case b.SecretRef != nil:
+				s := &corev1.Secret{}
+				if err := kube.Get(ctx, client.ObjectKey{Namespace: b.SecretRef.Namespace, Name: b.SecretRef.Name}, s); err != nil {
+					return nil, errors.Wrap(err, errGetCABundle)
+				}
+				bundle = s.Data[b.SecretRef.Key]
+			case b.ConfigMapRef != nil:
+				cm := &corev1.ConfigMap{}
+				if err := kube.Get(ctx, client.ObjectKey{Namespace: b.ConfigMapRef.Namespace, Name: b.ConfigMapRef.Name}, cm); err != nil {
+					return nil, errors.Wrap(err, errGetCABundle)
+				}
+				bundle = []byte(cm.Data[b.ConfigMapRef.Key])
+			}
+			opts = append(opts, grafana.WithCABundle(bundle))
+		}
+		if ref := t.ClientCertSecretRef; ref != nil {
+			s := &corev1.Secret{}
+			if err := kube.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+				return nil, errors.Wrap(err, errGetClientCert)
+			}
+			opts = append(opts, grafana.WithClientCertificate(s.Data[corev1.TLSCertKey], s.Data[corev1.TLSPrivateKeyKey]))
+		}
+	}
+
+	if h := spec.HTTP; h != nil {
+		if h.Timeout != nil {
+			opts = append(opts, grafana.WithTimeout(h.Timeout.Duration))
+		}
+		var wait time.Duration
+		if h.RetryWait != nil {
+			wait = h.RetryWait.Duration
+		}
+		opts = append(opts, grafana.WithRetries(h.Retries, wait))
+		if len(h.Headers) > 0 {
+			opts = append(opts, grafana.WithHeaders(h.Headers))
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+
+	apisv1alpha1 "github.com/loafoe/provider-orgmapper/apis/v1alpha1"
+)
+
+func TestClientOptions(t *testing.T) {
+	ca := &corev1.ConfigMap{}
+	ca.SetNamespace("crossplane-system")
+	ca.SetName("grafana-ca")
+	ca.Data = map[string]string{"ca.crt": "bundle"}
+
+	cases := map[string]struct {
+		reason   string
+		spec     apisv1alpha1.ProviderConfigSpec
+		wantOpts int
+		wantErr  bool
+	}{
+		"Defaults": {
+			reason: "Should not configure the transport when no TLS or HTTP settings are set.",
+		},
+		"TLSAndHTTP": {
+			reason: "Should resolve the CA bundle from a ConfigMap and configure timeouts and retries.",
+			spec: apisv1alpha1.ProviderConfigSpec{
+				TLS: &apisv1alpha1.TLSConfig{
+					CABundle: &apisv1alpha1.CABundleSource{
+						ConfigMapRef: &apisv1alpha1.ConfigMapKeySelector{Namespace: "crossplane-system", Name: "grafana-ca", Key: "ca.crt"},
+					},
+				},
+				HTTP: &apisv1alpha1.HTTPConfig{Timeout: &metav1.Duration{Duration: time.Second}, Retries: 3},
+			},
+			wantOpts: 3,
+		},
+		"MissingClientCertificate": {
+			reason: "Should return an error when the client certificate Secret does not exist.",
+			spec: apisv1alpha1.ProviderConfigSpec{
+				TLS: &apisv1alpha1.TLSConfig{
+					ClientCertSecretRef: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "grafana-client"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := fake.NewClientBuilder().WithObjects(ca).Build()
+			opts, err := ClientOptions(context.Background(), kube, &tc.spec)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("\n%s\nClientOptions(...): got error %v, want error %v", tc.reason, err, tc.wantErr)
+			}
+			if len(opts) != tc.wantOpts {
+				t.Errorf("\n%s\nClientOptions(...): got %d options, want %d", tc.reason, len(opts), tc.wantOpts)
+			}
+		})
+	}
+}
+
+func TestSSOProviders(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		spec   apisv1alpha1.ProviderConfigSpec
+		want   []string
+	}{
+		"Default": {
+			reason: "Should write to the generic_oauth provider when none is listed.",
+			want:   []string{"generic_oauth"},
+		},
+		"Listed": {
+			reason: "Should write to the listed providers.",
+			spec:   apisv1alpha1.ProviderConfigSpec{SSOProviders: []apisv1alpha1.SSOProvider{"azuread", "okta"}},
+			want:   []string{"azuread", "okta"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, SSOProviders(&tc.spec)); diff != "" {
+				t.Errorf("\n%s\nSSOProviders(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
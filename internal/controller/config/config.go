@@ -27,13 +27,19 @@ import (
 	"github.com/loafoe/provider-orgmapper/apis/v1alpha1"
 )
 
-// Setup adds a controller that reconciles ProviderConfigs by accounting for
-// their current usage.
+// Setup adds controllers that reconcile ProviderConfigs by accounting for
+// their current usage and probing the health of their Grafana instance.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	if err := setupNamespacedProviderConfig(mgr, o); err != nil {
 		return err
 	}
-	return setupClusterProviderConfig(mgr, o)
+	if err := setupClusterProviderConfig(mgr, o); err != nil {
+		return err
+	}
+	if err := setupHealth(mgr, o, v1alpha1.ProviderConfigGroupKind, func() resource.ProviderConfig { return &v1alpha1.ProviderConfig{} }); err != nil {
+		return err
+	}
+	return setupHealth(mgr, o, v1alpha1.ClusterProviderConfigGroupKind, func() resource.ProviderConfig { return &v1alpha1.ClusterProviderConfig{} })
 }
 
 func setupNamespacedProviderConfig(mgr ctrl.Manager, o controller.Options) error {
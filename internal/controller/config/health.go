@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/providerconfig"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/loafoe/provider-orgmapper/apis/v1alpha1"
+	"github.com/loafoe/provider-orgmapper/internal/clients"
+	"github.com/loafoe/provider-orgmapper/internal/grafana"
+)
+
+const (
+	errGetConfig    = "cannot get provider config"
+	errUpdateStatus = "cannot update provider config status"
+	errUnhealthy    = "Grafana health check failed"
+
+	// defaultHealthInterval is used when no poll interval is configured.
+	defaultHealthInterval = time.Minute
+)
+
+// A probeFn probes the Grafana instance configured by a ProviderConfig spec
+// and returns its version.
+type probeFn func(ctx context.Context, spec *v1alpha1.ProviderConfigSpec) (string, error)
+
+// setupHealth adds a controller that periodically probes the Grafana instance
+// of each config of the given kind and reports the result in its status.
+func setupHealth(mgr ctrl.Manager, o controller.Options, gk string, newConfig func() resource.ProviderConfig) error {
+	name := "health/" + providerconfig.ControllerName(gk)
+
+	interval := o.PollInterval
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	kube := mgr.GetClient()
+	r := &healthReconciler{
+		kube:      kube,
+		newConfig: newConfig,
+		probe: func(ctx context.Context, spec *v1alpha1.ProviderConfigSpec) (string, error) {
+			gClient, err := clients.NewGrafanaClient(ctx, kube, spec)
+			if err != nil {
+				return "", err
+			}
+			return grafana.CheckHealth(gClient.Health, gClient.SsoSettings, clients.SSOProviders(spec)...)
+		},
+		interval: interval,
+		log:      o.Logger.WithValues("controller", name),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(newConfig(), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// healthReconciler sets the Ready condition and Grafana version of a
+// ProviderConfig or ClusterProviderConfig. A wrong URL or expired credentials
+// are reported on the config itself rather than on the first Tenant that
+// fails to sync.
+type healthReconciler struct {
+	kube      client.Client
+	newConfig func() resource.ProviderConfig
+	probe     probeFn
+	interval  time.Duration
+	log       logging.Logger
+}
+
+func (r *healthReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	pc := r.newConfig()
+	if err := r.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		return reconcile.Result{}, errors.Wrap(client.IgnoreNotFound(err), errGetConfig)
+	}
+	if pc.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	spec, status := specAndStatus(pc)
+	version, err := r.probe(ctx, spec)
+
+	orig := pc.DeepCopyObject().(client.Object)
+	if err != nil {
+		r.log.Debug(errUnhealthy, "config", types.NamespacedName{Namespace: pc.GetNamespace(), Name: pc.GetName()}, "error", err)
+		pc.SetConditions(xpv1.Unavailable().WithMessage(err.Error()))
+	} else {
+		pc.SetConditions(xpv1.Available())
+	}
+	if version != "" {
+		status.GrafanaVersion = version
+	}
+
+	// Patch with an optimistic lock so that the usage reconciler, which
+	// updates the same status, is never overwritten.
+	if err := r.kube.Status().Patch(ctx, pc, client.MergeFromWithOptions(orig, client.MergeFromWithOptimisticLock{})); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+	return reconcile.Result{RequeueAfter: r.interval}, nil
+}
+
+// specAndStatus returns the spec and status shared by both config kinds.
+func specAndStatus(pc resource.ProviderConfig) (*v1alpha1.ProviderConfigSpec, *v1alpha1.ProviderConfigStatus) {
+	switch pc := pc.(type) {
+	case *v1alpha1.ProviderConfig:
+		return &pc.Spec, &pc.Status
+	case *v1alpha1.ClusterProviderConfig:
+		return &pc.Spec, &pc.Status
+	}
+	return &v1alpha1.ProviderConfigSpec{}, &v1alpha1.ProviderConfigStatus{}
+}
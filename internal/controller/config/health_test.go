@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	"github.com/loafoe/provider-orgmapper/apis/v1alpha1"
+)
+
+func TestHealthReconcile(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		probe       probeFn
+		wantStatus  corev1.ConditionStatus
+		wantMessage string
+		wantVersion string
+	}{
+		"Healthy": {
+			reason:      "Should mark the config Ready and record the Grafana version.",
+			probe:       func(context.Context, *v1alpha1.ProviderConfigSpec) (string, error) { return "11.3.0", nil },
+			wantStatus:  corev1.ConditionTrue,
+			wantVersion: "11.3.0",
+		},
+		"Unhealthy": {
+			reason:      "Should mark the config not Ready with the reason the probe failed.",
+			probe:       func(context.Context, *v1alpha1.ProviderConfigSpec) (string, error) { return "", errors.New("boom") },
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: "boom",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &v1alpha1.ClusterProviderConfig{}
+			pc.SetName("prod")
+
+			scheme := kruntime.NewScheme()
+			_ = v1alpha1.SchemeBuilder.AddToScheme(scheme)
+			kube := clfake.NewClientBuilder().WithScheme(scheme).WithObjects(pc).WithStatusSubresource(pc).Build()
+
+			r := &healthReconciler{
+				kube:      kube,
+				newConfig: func() resource.ProviderConfig { return &v1alpha1.ClusterProviderConfig{} },
+				probe:     tc.probe,
+				interval:  time.Minute,
+				log:       logging.NewNopLogger(),
+			}
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod"}})
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(reconcile.Result{RequeueAfter: time.Minute}, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			updated := &v1alpha1.ClusterProviderConfig{}
+			if err := kube.Get(context.Background(), types.NamespacedName{Name: "prod"}, updated); err != nil {
+				t.Fatalf("kube.Get(...): unexpected error: %v", err)
+			}
+			c := updated.GetCondition(xpv1.TypeReady)
+			if c.Status != tc.wantStatus || c.Message != tc.wantMessage {
+				t.Errorf("\n%s\nReady condition: got %s %q, want %s %q", tc.reason, c.Status, c.Message, tc.wantStatus, tc.wantMessage)
+			}
+			if updated.Status.GrafanaVersion != tc.wantVersion {
+				t.Errorf("\n%s\nGrafanaVersion: got %q, want %q", tc.reason, updated.Status.GrafanaVersion, tc.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groupset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+)
+
+func TestConnect(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   error
+	}{
+		"GroupSet": {
+			reason: "Should connect without a ProviderConfig.",
+			mg:     &v1alpha1.GroupSet{},
+		},
+		"NotGroupSet": {
+			reason: "Should return an error for other managed resources.",
+			mg:     &v1alpha1.Tenant{},
+			want:   errors.New(errNotGroupSet),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&connector{}).Connect(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	deleting := &v1alpha1.GroupSet{}
+	deleting.SetDeletionTimestamp(&metav1.Time{})
+
+	cases := map[string]struct {
+		reason   string
+		mg       resource.Managed
+		want     managed.ExternalObservation
+		wantCond xpv1.Condition
+		wantErr  error
+	}{
+		"Available": {
+			reason:   "Should report an existing GroupSet as up to date and available.",
+			mg:       &v1alpha1.GroupSet{},
+			want:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			wantCond: xpv1.Available(),
+		},
+		"Deleting": {
+			reason:   "Should report a deleting GroupSet as gone so that its finalizer is removed.",
+			mg:       deleting,
+			want:     managed.ExternalObservation{ResourceExists: false},
+			wantCond: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+		},
+		"NotGroupSet": {
+			reason:   "Should return an error for other managed resources.",
+			mg:       &v1alpha1.Tenant{},
+			wantCond: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+			wantErr:  errors.New(errNotGroupSet),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := (&external{}).Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\ne.Observe(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantCond, tc.mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMutations(t *testing.T) {
+	e := &external{}
+	cr := &v1alpha1.GroupSet{}
+	cr.Spec.ForProvider.ViewerGroups = []string{"viewers"}
+	want := cr.DeepCopy()
+
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Errorf("e.Create(...): unexpected error: %v", err)
+	}
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Errorf("e.Update(...): unexpected error: %v", err)
+	}
+	if _, err := e.Delete(context.Background(), cr); err != nil {
+		t.Errorf("e.Delete(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, cr); diff != "" {
+		t.Errorf("external: GroupSet changed: -want, +got:\n%s", diff)
+	}
+}
@@ -17,18 +17,27 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/loafoe/provider-orgmapper/internal/controller/config"
+	"github.com/loafoe/provider-orgmapper/internal/controller/groupset"
 	"github.com/loafoe/provider-orgmapper/internal/controller/tenant"
 )
 
 // Setup creates all OrgMapper controllers and adds them to the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// Changes to Tenants are written to the org_mapping of their Grafana instance
+// once they stopped for the debounce window.
+func Setup(mgr ctrl.Manager, o controller.Options, debounce time.Duration) error {
 	for _, setup := range []func(ctrl.Manager, controller.Options) error{
 		config.Setup,
+		groupset.Setup,
 		tenant.Setup,
+		func(mgr ctrl.Manager, o controller.Options) error {
+			return tenant.SetupOrgMapping(mgr, o, debounce)
+		},
 	} {
 		if err := setup(mgr, o); err != nil {
 			return err
@@ -36,3 +45,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	}
 	return nil
 }
+
+// SetupWebhooks adds all OrgMapper admission webhooks to the supplied manager.
+func SetupWebhooks(mgr ctrl.Manager) error {
+	return tenant.SetupWebhook(mgr)
+}
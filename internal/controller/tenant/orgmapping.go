@@ -0,0 +1,649 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/providerconfig"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+	apisv1alpha1 "github.com/loafoe/provider-orgmapper/apis/v1alpha1"
+	"github.com/loafoe/provider-orgmapper/internal/clients"
+	"github.com/loafoe/provider-orgmapper/internal/grafana"
+)
+
+const (
+	errSyncOrgMapping = "cannot sync Grafana org mapping"
+	errRecordManaged  = "cannot record org_mapping sync on ProviderConfig"
+	errPublishMapping = "cannot publish org_mapping status"
+
+	// defaultMappingInterval is used when no poll interval is configured.
+	defaultMappingInterval = time.Minute
+)
+
+// SetupOrgMapping adds controllers that write the org_mapping of all Tenants
+// and ClusterTenants using a ProviderConfig or ClusterProviderConfig to its
+// Grafana instance. A config is synced once its Tenants and GroupSets have not
+// changed for the debounce window, so that a bulk change results in a single
+// write. The result
+// of every sync is published in an OrgMapping or ClusterOrgMapping of the same
+// name as the config.
+func SetupOrgMapping(mgr ctrl.Manager, o controller.Options, debounce time.Duration) error {
+	if err := setupOrgMapping(mgr, o, apisv1alpha1.ProviderConfigKind, apisv1alpha1.ProviderConfigGroupKind, debounce,
+		func() resource.ProviderConfig { return &apisv1alpha1.ProviderConfig{} },
+		func() client.Object { return &apisv1alpha1.OrgMapping{} }); err != nil {
+		return err
+	}
+	return setupOrgMapping(mgr, o, apisv1alpha1.ClusterProviderConfigKind, apisv1alpha1.ClusterProviderConfigGroupKind, debounce,
+		func() resource.ProviderConfig { return &apisv1alpha1.ClusterProviderConfig{} },
+		func() client.Object { return &apisv1alpha1.ClusterOrgMapping{} })
+}
+
+// setupOrgMapping adds the org_mapping controller of the configs of the
+// given kind.
+func setupOrgMapping(mgr ctrl.Manager, o controller.Options, kind, gk string, debounce time.Duration, newConfig func() resource.ProviderConfig, newMapping func() client.Object) error {
+	name := "orgmapping/" + providerconfig.ControllerName(gk)
+
+	interval := o.PollInterval
+	if interval <= 0 {
+		interval = defaultMappingInterval
+	}
+	kube := mgr.GetClient()
+	r := &mappingReconciler{
+		kube:       kube,
+		kind:       kind,
+		newConfig:  newConfig,
+		newMapping: newMapping,
+		newSSO: func(ctx context.Context, spec *apisv1alpha1.ProviderConfigSpec) (grafana.SSOClient, error) {
+			gClient, err := clients.NewGrafanaClient(ctx, kube, spec)
+			if err != nil {
+				return nil, err
+			}
+			return gClient.SsoSettings, nil
+		},
+		interval: interval,
+		log:      o.Logger.WithValues("controller", name),
+	}
+	if debounce > 0 {
+		r.debounce = newDebouncer(debounce)
+	}
+	enqueue := &enqueueProviderConfig{kube: kube, kind: kind, debounce: r.debounce, log: r.log}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(newConfig(), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(newMapping(), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&v1alpha1.Tenant{}, enqueue, builder.WithPredicates(mappingInputChanged())).
+		Watches(&v1alpha1.ClusterTenant{}, enqueue, builder.WithPredicates(mappingInputChanged())).
+		Watches(&v1alpha1.GroupSet{}, enqueue, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// mappingInputChanged filters out Tenant updates that cannot change the
+// org_mapping, such as the status updates made by every reconcile.
+func mappingInputChanged() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+			o, ok := e.ObjectOld.(tenantObject)
+			if !ok {
+				return true
+			}
+			n, ok := e.ObjectNew.(tenantObject)
+			if !ok {
+				return true
+			}
+			return o.GetGeneration() != n.GetGeneration() ||
+				orgIDOf(o) != orgIDOf(n) ||
+				(o.GetDeletionTimestamp() == nil) != (n.GetDeletionTimestamp() == nil)
+		},
+	}
+}
+
+// orgMappingSyncChanged filters config events down to updates that record
+// another org_mapping sync, or change whether the last one succeeded.
+func orgMappingSyncChanged() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(ctrlevent.CreateEvent) bool { return false },
+		DeleteFunc:  func(ctrlevent.DeleteEvent) bool { return false },
+		GenericFunc: func(ctrlevent.GenericEvent) bool { return false },
+		UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+			o, ok := e.ObjectOld.(resource.ProviderConfig)
+			if !ok {
+				return false
+			}
+			n, ok := e.ObjectNew.(resource.ProviderConfig)
+			if !ok {
+				return false
+			}
+			was, is := newProviderConfig(o).status, newProviderConfig(n).status
+			if !equality.Semantic.DeepEqual(was.OrgMappingSync, is.OrgMappingSync) {
+				return true
+			}
+			oc, nc := was.GetCondition(apisv1alpha1.TypeOrgMappingSynced), is.GetCondition(apisv1alpha1.TypeOrgMappingSynced)
+			return oc.Status != nc.Status || oc.Message != nc.Message
+		},
+	}
+}
+
+// configTenants returns a function that maps a config of the given kind to
+// the Tenants, or the ClusterTenants if cluster is true, using it. The Tenant
+// controllers use it to update the OrgMappingSynced condition of each Tenant
+// as soon as a sync is recorded, rather than on their next poll.
+func configTenants(kube client.Reader, kind string, cluster bool, log logging.Logger) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		tenants, err := listTenants(ctx, kube)
+		if err != nil {
+			log.Info(errListTenants, "error", err)
+			return nil
+		}
+		target := providerConfigKey{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		var reqs []reconcile.Request
+		for _, t := range tenants {
+			if _, ok := t.(*v1alpha1.ClusterTenant); ok != cluster || providerConfigKeyOf(t) != target {
+				continue
+			}
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(t)})
+		}
+		return reqs
+	}
+}
+
+// debouncer tracks, per config, when the debounce window that started with
+// the last change to its Tenants ends. Each change restarts the window, so a
+// config is only synced once its Tenants stopped changing.
+type debouncer struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	deadlines map[types.NamespacedName]time.Time
+}
+
+// newDebouncer returns a debouncer with the given window.
+func newDebouncer(window time.Duration) *debouncer {
+	return &debouncer{window: window, now: time.Now, deadlines: map[types.NamespacedName]time.Time{}}
+}
+
+// touch restarts the window of a config and returns its length.
+func (d *debouncer) touch(key types.NamespacedName) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deadlines[key] = d.now().Add(d.window)
+	return d.window
+}
+
+// wait returns how long the window of a config is still open, or zero once
+// it has passed.
+func (d *debouncer) wait(key types.NamespacedName) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	deadline, ok := d.deadlines[key]
+	if !ok {
+		return 0
+	}
+	if wait := deadline.Sub(d.now()); wait > 0 {
+		return wait
+	}
+	delete(d.deadlines, key)
+	return 0
+}
+
+// enqueueProviderConfig enqueues the config of the given kind that a Tenant,
+// or the Tenants referencing a GroupSet, use. With a debouncer, requests are
+// delayed by its window and every change restarts it; the reconciler requeues
+// a request that arrives before the window of its config has passed.
+type enqueueProviderConfig struct {
+	kube     client.Reader
+	kind     string
+	debounce *debouncer
+	log      logging.Logger
+}
+
+func (e *enqueueProviderConfig) Create(ctx context.Context, ev ctrlevent.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueue(ctx, ev.Object, q)
+}
+
+func (e *enqueueProviderConfig) Update(ctx context.Context, ev ctrlevent.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	// A Tenant may have moved to another config, which must drop its entries.
+	e.enqueue(ctx, ev.ObjectOld, q)
+	e.enqueue(ctx, ev.ObjectNew, q)
+}
+
+func (e *enqueueProviderConfig) Delete(ctx context.Context, ev ctrlevent.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueue(ctx, ev.Object, q)
+}
+
+func (e *enqueueProviderConfig) Generic(ctx context.Context, ev ctrlevent.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueue(ctx, ev.Object, q)
+}
+
+var _ handler.EventHandler = &enqueueProviderConfig{}
+
+func (e *enqueueProviderConfig) enqueue(ctx context.Context, obj client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	for _, key := range e.providerConfigsOf(ctx, obj) {
+		if key.Kind != e.kind {
+			continue
+		}
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: key.Namespace, Name: key.Name}}
+		if e.debounce == nil {
+			q.Add(req)
+			continue
+		}
+		q.AddAfter(req, e.debounce.touch(req.NamespacedName))
+	}
+}
+
+// providerConfigsOf returns the configs used by a Tenant, or by the Tenants
+// referencing a GroupSet.
+func (e *enqueueProviderConfig) providerConfigsOf(ctx context.Context, obj client.Object) []providerConfigKey {
+	switch o := obj.(type) {
+	case tenantObject:
+		return []providerConfigKey{providerConfigKeyOf(o)}
+	case *v1alpha1.GroupSet:
+		tenants, err := groupSetTenants(ctx, e.kube, o)
+		if err != nil {
+			e.log.Info(errListTenants, "error", err)
+			return nil
+		}
+		keys := make([]providerConfigKey, 0, len(tenants))
+		for _, t := range tenants {
+			keys = append(keys, providerConfigKeyOf(t))
+		}
+		return keys
+	}
+	return nil
+}
+
+// mappingReconciler computes the org_mapping of all Tenants and
+// ClusterTenants using a ProviderConfig or ClusterProviderConfig and writes it
+// to the SSO providers that do not hold it already. It records the Tenants
+// included in its last successful sync in the status of the config, and the
+// rendered org_mapping in an OrgMapping or ClusterOrgMapping.
+type mappingReconciler struct {
+	kube       client.Client
+	kind       string
+	newConfig  func() resource.ProviderConfig
+	newMapping func() client.Object
+	newSSO     func(ctx context.Context, spec *apisv1alpha1.ProviderConfigSpec) (grafana.SSOClient, error)
+	debounce   *debouncer
+	interval   time.Duration
+	log        logging.Logger
+}
+
+func (r *mappingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if r.debounce != nil {
+		if wait := r.debounce.wait(req.NamespacedName); wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	obj := r.newConfig()
+	if err := r.kube.Get(ctx, req.NamespacedName, obj); err != nil {
+		return reconcile.Result{}, errors.Wrap(client.IgnoreNotFound(err), errGetPC)
+	}
+	if obj.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	pc := newProviderConfig(obj)
+	orig := obj.DeepCopyObject().(client.Object)
+	report, err := r.sync(ctx, pc)
+	if err != nil {
+		r.log.Info("Failed to sync Grafana org mapping", "config", req.NamespacedName, "error", err)
+		pc.status.SetConditions(apisv1alpha1.OrgMappingSyncFailed(err))
+	} else {
+		pc.status.SetConditions(apisv1alpha1.OrgMappingSynced())
+	}
+
+	// The recorded managedOrgMapping decides which entries Owned mode may
+	// remove later, so a status read before a concurrent update must not be
+	// written back; a conflict retries the request instead.
+	if perr := r.kube.Status().Patch(ctx, obj, client.MergeFromWithOptions(orig, client.MergeFromWithOptimisticLock{})); perr != nil {
+		return reconcile.Result{}, errors.Wrap(perr, errRecordManaged)
+	}
+	if perr := r.publish(ctx, obj, report, err); perr != nil {
+		return reconcile.Result{}, errors.Wrap(perr, errPublishMapping)
+	}
+	// Returning the error makes the request back off.
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.interval}, nil
+}
+
+// sync writes the org_mapping of the Tenants using a config to every SSO
+// provider whose org_mapping or managed settings differ from it, and records
+// the result in the status of the config. It returns what was rendered and
+// found in Grafana.
+func (r *mappingReconciler) sync(ctx context.Context, pc *providerConfig) (*apisv1alpha1.OrgMappingStatus, error) {
+	tenants, err := r.tenantsOf(ctx, pc)
+	if err != nil {
+		return nil, err
+	}
+	groupSets, err := listGroupSets(ctx, r.kube)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &apisv1alpha1.OrgMappingStatus{}
+	mappings := make([]grafana.TenantMapping, 0, len(tenants))
+	included := make(map[string]int64, len(tenants))
+	for _, t := range tenants {
+		// Skip tenants whose Grafana organization has not been created yet.
+		if orgIDOf(t) == "" {
+			continue
+		}
+		mappings = append(mappings, tenantMapping(t, groupSets))
+		included[string(t.GetUID())] = t.GetGeneration()
+		report.Tenants = append(report.Tenants, contributor(t))
+	}
+	desired := grafana.BuildOrgMappingEntries(mappings)
+	expected := make([]grafana.OrgMappingEntry, 0, len(desired))
+	for _, m := range mappings {
+		expected = append(expected, grafana.TenantEntries(m)...)
+	}
+
+	sso, err := r.newSSO(ctx, pc.spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		opts  []grafana.SyncOption
+		owned map[string]bool
+	)
+	if pc.spec.OrgMappingMode == apisv1alpha1.OrgMappingModeOwned {
+		opts = append(opts, grafana.WithOwnedEntries(pc.status.ManagedOrgMapping))
+		owned = make(map[string]bool, len(pc.status.ManagedOrgMapping)+len(desired))
+		for _, e := range pc.status.ManagedOrgMapping {
+			owned[e] = true
+		}
+		for _, e := range desired {
+			owned[e] = true
+		}
+	}
+	settings := ssoSettings(pc.spec)
+
+	written := false
+	var missing []string
+	for _, p := range clients.SSOProviders(pc.spec) {
+		current, err := providerSettings(sso, p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s for SSO provider %s", errSyncOrgMapping, p)
+		}
+		applied := maps.Clone(current)
+		maps.Copy(applied, settings)
+		if !grafana.HasOrgAttributePath(applied) {
+			missing = append(missing, p)
+		}
+		held, _ := current["orgMapping"].(string)
+		status := apisv1alpha1.OrgMappingProvider{Name: p, ForeignEntries: foreignEntries(held, expected, owned)}
+		if mappingDrifted(current, settings, expected, owned) {
+			r.log.Debug("Syncing Grafana org mapping", "provider", p, "entries", len(desired))
+			if _, err := grafana.SyncOrgMapping(ctx, sso, mappings, append(opts, grafana.WithProvider(p), grafana.WithSettings(settings))...); err != nil {
+				return nil, errors.Wrapf(err, "%s for SSO provider %s", errSyncOrgMapping, p)
+			}
+			written = true
+			// Grafana now holds what SyncOrgMapping rendered.
+			entries := desired
+			if owned != nil {
+				entries = grafana.MergeOrgMappingEntries(grafana.SplitOrgMapping(held), pc.status.ManagedOrgMapping, desired)
+			}
+			held = strings.Join(entries, ",")
+		}
+		status.Hash = fmt.Sprintf("%x", sha256.Sum256([]byte(held)))
+		report.Providers = append(report.Providers, status)
+	}
+
+	pc.status.ManagedOrgMapping = desired
+	if len(missing) > 0 {
+		r.log.Info("Grafana ignores org_mapping without an orgAttributePath", "providers", missing)
+		pc.status.SetConditions(apisv1alpha1.OrgAttributePathMissing(missing))
+	} else {
+		pc.status.SetConditions(apisv1alpha1.OrgMappingEffective())
+	}
+
+	last := pc.status.OrgMappingSync
+	if last == nil || written || !maps.Equal(last.Tenants, included) {
+		next := &apisv1alpha1.OrgMappingSync{LastSyncTime: &metav1.Time{Time: time.Now().UTC().Truncate(time.Second)}, Tenants: included}
+		if last != nil {
+			next.Generation = last.Generation
+		}
+		next.Generation++
+		pc.status.OrgMappingSync = next
+	}
+
+	report.Entries = desired
+	report.SyncGeneration = pc.status.OrgMappingSync.Generation
+	report.LastSyncTime = pc.status.OrgMappingSync.LastSyncTime
+	slices.SortFunc(report.Tenants, func(a, b apisv1alpha1.OrgMappingTenant) int {
+		return strings.Compare(a.Kind+"/"+a.Namespace+"/"+a.Name, b.Kind+"/"+b.Namespace+"/"+b.Name)
+	})
+	return report, nil
+}
+
+// contributor returns how a Tenant contributing to an org_mapping is shown in
+// its OrgMapping.
+func contributor(t tenantObject) apisv1alpha1.OrgMappingTenant {
+	kind := v1alpha1.TenantKind
+	if _, ok := t.(*v1alpha1.ClusterTenant); ok {
+		kind = v1alpha1.ClusterTenantKind
+	}
+	return apisv1alpha1.OrgMappingTenant{
+		Kind:      kind,
+		Namespace: t.GetNamespace(),
+		Name:      t.GetName(),
+		TenantID:  t.GetParameters().TenantID,
+		OrgID:     orgIDOf(t),
+	}
+}
+
+// publish records the result of a sync in the OrgMapping or ClusterOrgMapping
+// named after the config, creating it if needed. A failed sync only updates
+// the last error, so that the last rendered org_mapping stays visible.
+func (r *mappingReconciler) publish(ctx context.Context, pc client.Object, report *apisv1alpha1.OrgMappingStatus, syncErr error) error {
+	m := r.newMapping()
+	err := r.kube.Get(ctx, types.NamespacedName{Namespace: pc.GetNamespace(), Name: pc.GetName()}, m)
+	if kerrors.IsNotFound(err) {
+		m.SetNamespace(pc.GetNamespace())
+		m.SetName(pc.GetName())
+		meta.AddOwnerReference(m, meta.AsController(meta.TypedReferenceTo(pc, apisv1alpha1.SchemeGroupVersion.WithKind(r.kind))))
+		err = r.kube.Create(ctx, m)
+	}
+	if err != nil {
+		return err
+	}
+
+	status := mappingStatusOf(m)
+	want := status.DeepCopy()
+	if syncErr != nil {
+		want.LastError = syncErr.Error()
+	} else {
+		want = report
+	}
+	if equality.Semantic.DeepEqual(status, want) {
+		return nil
+	}
+	*status = *want
+	return r.kube.Status().Update(ctx, m)
+}
+
+// mappingStatusOf returns the status of an OrgMapping or ClusterOrgMapping.
+func mappingStatusOf(obj client.Object) *apisv1alpha1.OrgMappingStatus {
+	switch m := obj.(type) {
+	case *apisv1alpha1.OrgMapping:
+		return &m.Status
+	case *apisv1alpha1.ClusterOrgMapping:
+		return &m.Status
+	}
+	return &apisv1alpha1.OrgMappingStatus{}
+}
+
+// foreignEntries returns the entries of an org_mapping value that were not
+// rendered from Tenants. In Owned mode, entries the provider wrote before are
+// not foreign either.
+func foreignEntries(orgMapping string, expected []grafana.OrgMappingEntry, owned map[string]bool) []string {
+	want := make(map[grafana.OrgMappingEntry]bool, len(expected))
+	for _, e := range expected {
+		want[e] = true
+	}
+	var foreign []string
+	for _, e := range grafana.SplitOrgMapping(orgMapping) {
+		if owned[e] {
+			continue
+		}
+		if parsed := grafana.ParseOrgMapping(e); len(parsed) == 1 && want[parsed[0]] {
+			continue
+		}
+		foreign = append(foreign, e)
+	}
+	return foreign
+}
+
+// tenantsOf returns the Tenants and ClusterTenants using a config that are
+// not being deleted.
+func (r *mappingReconciler) tenantsOf(ctx context.Context, pc *providerConfig) ([]tenantObject, error) {
+	all, err := listTenants(ctx, r.kube)
+	if err != nil {
+		return nil, err
+	}
+	target := providerConfigKey{Kind: r.kind, Namespace: pc.obj.GetNamespace(), Name: pc.obj.GetName()}
+	tenants := make([]tenantObject, 0, len(all))
+	for _, t := range all {
+		if t.GetDeletionTimestamp() != nil || providerConfigKeyOf(t) != target {
+			continue
+		}
+		tenants = append(tenants, t)
+	}
+	return tenants, nil
+}
+
+// providerSettings returns the current settings of an SSO provider, or no
+// settings if the provider is not configured yet.
+func providerSettings(sso grafana.SSOClient, provider string) (map[string]any, error) {
+	resp, err := sso.GetProviderSettings(provider)
+	if grafana.IsNotFound(err) {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	settings, ok := resp.Payload.Settings.(map[string]any)
+	if !ok {
+		return nil, errors.New("SSO settings is not a map")
+	}
+	return settings, nil
+}
+
+// mappingDrifted reports whether the current settings of an SSO provider do
+// not hold exactly the expected org_mapping entries, or differ in a managed
+// setting. The order of entries is ignored. When owned is not nil (Owned
+// mode) only the entries it holds are considered, so manually managed entries
+// never cause drift.
+func mappingDrifted(current, settings map[string]any, expected []grafana.OrgMappingEntry, owned map[string]bool) bool {
+	if grafana.SettingsDrifted(current, settings) {
+		return true
+	}
+
+	orgMapping, _ := current["orgMapping"].(string)
+	actual := map[grafana.OrgMappingEntry]bool{}
+	for _, e := range grafana.ParseOrgMapping(orgMapping) {
+		if owned != nil && !owned[e.String()] {
+			continue
+		}
+		actual[e] = true
+	}
+
+	want := make(map[grafana.OrgMappingEntry]bool, len(expected))
+	for _, e := range expected {
+		want[e] = true
+	}
+	return !maps.Equal(want, actual)
+}
+
+// ssoSettings returns the SSO settings a ProviderConfig manages besides
+// org_mapping, keyed as in the Grafana API.
+func ssoSettings(spec *apisv1alpha1.ProviderConfigSpec) map[string]any {
+	settings := map[string]any{}
+	if spec.SSOSettings == nil {
+		return settings
+	}
+	s := spec.SSOSettings
+	for k, v := range map[string]*string{
+		"roleAttributePath":   s.RoleAttributePath,
+		"orgAttributePath":    s.OrgAttributePath,
+		"groupsAttributePath": s.GroupsAttributePath,
+	} {
+		if v != nil {
+			settings[k] = *v
+		}
+	}
+	for k, v := range map[string]*bool{
+		"roleAttributeStrict":     s.RoleAttributeStrict,
+		"allowAssignGrafanaAdmin": s.AllowAssignGrafanaAdmin,
+		"skipOrgRoleSync":         s.SkipOrgRoleSync,
+	} {
+		if v != nil {
+			settings[k] = *v
+		}
+	}
+	return settings
+}
+
+// orgMappingCondition reports whether the last successful org_mapping sync of
+// a config included the current generation of a Tenant using it.
+func orgMappingCondition(cr tenantObject, status *apisv1alpha1.ProviderConfigStatus) xpv1.Condition {
+	if s := status.OrgMappingSync; s != nil {
+		if g, ok := s.Tenants[string(cr.GetUID())]; ok && g == cr.GetGeneration() {
+			return v1alpha1.OrgMappingSynced(s.Generation, g)
+		}
+	}
+	if c := status.GetCondition(apisv1alpha1.TypeOrgMappingSynced); c.Status == corev1.ConditionFalse {
+		return v1alpha1.OrgMappingSyncFailed(c.Message)
+	}
+	return v1alpha1.OrgMappingPending()
+}
@@ -0,0 +1,853 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+
+	"github.com/grafana/grafana-openapi-client-go/client/sso_settings"
+	"github.com/grafana/grafana-openapi-client-go/models"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+	apisv1alpha1 "github.com/loafoe/provider-orgmapper/apis/v1alpha1"
+	"github.com/loafoe/provider-orgmapper/internal/grafana"
+)
+
+// newMappingReconciler returns an org_mapping reconciler of configs of the
+// given kind that writes to the given SSO client.
+func newMappingReconciler(kube client.Client, kind string, sso grafana.SSOClient) *mappingReconciler {
+	newConfig := func() resource.ProviderConfig { return &apisv1alpha1.ClusterProviderConfig{} }
+	newMapping := func() client.Object { return &apisv1alpha1.ClusterOrgMapping{} }
+	if kind == apisv1alpha1.ProviderConfigKind {
+		newConfig = func() resource.ProviderConfig { return &apisv1alpha1.ProviderConfig{} }
+		newMapping = func() client.Object { return &apisv1alpha1.OrgMapping{} }
+	}
+	return &mappingReconciler{
+		kube:       kube,
+		kind:       kind,
+		newConfig:  newConfig,
+		newMapping: newMapping,
+		newSSO: func(context.Context, *apisv1alpha1.ProviderConfigSpec) (grafana.SSOClient, error) {
+			return sso, nil
+		},
+		interval: time.Minute,
+		log:      logging.NewNopLogger(),
+	}
+}
+
+// withSettings returns a mock SSO client whose providers hold the given
+// settings.
+func withSettings(settings map[string]any) *mockSSO {
+	return &mockSSO{getResp: &sso_settings.GetProviderSettingsOK{Payload: &models.GetProviderSettingsOKBody{Settings: settings}}}
+}
+
+// writtenOrgMapping returns the org_mapping last written to a mock SSO client.
+func writtenOrgMapping(sso *mockSSO) string {
+	if sso.putBody == nil {
+		return ""
+	}
+	settings, _ := sso.putBody.Settings.(map[string]any)
+	orgMapping, _ := settings["orgMapping"].(string)
+	return orgMapping
+}
+
+func TestMappingReconcile(t *testing.T) {
+	withPC := func(name, namespace, tenantID, orgID, kind, pcName string) *v1alpha1.Tenant {
+		cr := tenantWithSpec(tenantID, orgID, nil, v1alpha1.RetentionPolicy{})
+		cr.SetName(name)
+		cr.SetNamespace(namespace)
+		cr.SetUID(types.UID(name + "-uid"))
+		cr.SetGeneration(1)
+		cr.Spec.ForProvider.ViewerGroups = []string{tenantID + "-viewers"}
+		cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: kind, Name: pcName}
+		return cr
+	}
+
+	prodA := withPC("a", "team-a", "a", "1", "ClusterProviderConfig", "prod")
+	prodB := withPC("b", "team-b", "b", "2", "ClusterProviderConfig", "prod")
+	staging := withPC("c", "team-a", "c", "3", "ClusterProviderConfig", "staging")
+	nsA := withPC("d", "team-a", "d", "4", "ProviderConfig", "default")
+	nsB := withPC("e", "team-b", "e", "5", "ProviderConfig", "default")
+	nsEmptyKind := withPC("f", "team-a", "f", "6", "", "default")
+	deleting := withPC("h", "team-a", "h", "8", "ClusterProviderConfig", "prod")
+	deleting.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+	deleting.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	pending := withPC("i", "team-a", "i", "", "ClusterProviderConfig", "prod")
+	pending.Spec.ForProvider.CreateOrg = true
+
+	// ClusterTenants always use a ClusterProviderConfig, whatever the kind
+	// of their reference.
+	cluster := &v1alpha1.ClusterTenant{}
+	cluster.SetName("g")
+	cluster.SetUID("g-uid")
+	cluster.SetGeneration(3)
+	cluster.Spec.ForProvider = v1alpha1.TenantParameters{TenantID: "g", OrgID: "7", ViewerGroups: []string{"g-viewers"}}
+	cluster.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Name: "prod"}
+
+	prod := &apisv1alpha1.ClusterProviderConfig{}
+	prod.SetName("prod")
+	stagingPC := &apisv1alpha1.ClusterProviderConfig{}
+	stagingPC.SetName("staging")
+	nsPC := &apisv1alpha1.ProviderConfig{}
+	nsPC.SetName("default")
+	nsPC.SetNamespace("team-a")
+
+	cases := map[string]struct {
+		reason      string
+		kind        string
+		req         types.NamespacedName
+		sso         *mockSSO
+		want        string
+		wantTenants map[string]int64
+	}{
+		"ClusterProviderConfig": {
+			reason:      "Should include the tenants and cluster tenants of the ClusterProviderConfig regardless of namespace, except those being deleted or without an org.",
+			kind:        apisv1alpha1.ClusterProviderConfigKind,
+			req:         types.NamespacedName{Name: "prod"},
+			sso:         defaultMockSSO(),
+			want:        "a-viewers:1:Viewer,b-viewers:2:Viewer,g-viewers:7:Viewer",
+			wantTenants: map[string]int64{"a-uid": 1, "b-uid": 1, "g-uid": 3},
+		},
+		"OtherClusterProviderConfig": {
+			reason:      "Should not leak tenants of another ClusterProviderConfig.",
+			kind:        apisv1alpha1.ClusterProviderConfigKind,
+			req:         types.NamespacedName{Name: "staging"},
+			sso:         defaultMockSSO(),
+			want:        "c-viewers:3:Viewer",
+			wantTenants: map[string]int64{"c-uid": 1},
+		},
+		"NamespacedProviderConfig": {
+			reason:      "Should treat same-named ProviderConfigs in different namespaces as distinct, and an empty kind as ProviderConfig.",
+			kind:        apisv1alpha1.ProviderConfigKind,
+			req:         types.NamespacedName{Namespace: "team-a", Name: "default"},
+			sso:         defaultMockSSO(),
+			want:        "d-viewers:4:Viewer,f-viewers:6:Viewer",
+			wantTenants: map[string]int64{"d-uid": 1, "f-uid": 1},
+		},
+		"InSync": {
+			reason:      "Should not write an org_mapping Grafana already holds, whatever the order of its entries.",
+			kind:        apisv1alpha1.ClusterProviderConfigKind,
+			req:         types.NamespacedName{Name: "prod"},
+			sso:         withSettings(map[string]any{"orgMapping": "g-viewers:7:Viewer, a-viewers:1:Viewer,b-viewers:2"}),
+			wantTenants: map[string]int64{"a-uid": 1, "b-uid": 1, "g-uid": 3},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := newFakeKube(prodA, prodB, staging, nsA, nsB, nsEmptyKind, deleting, pending, cluster, prod, stagingPC, nsPC)
+			r := newMappingReconciler(kube, tc.kind, tc.sso)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: tc.req})
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(reconcile.Result{RequeueAfter: time.Minute}, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, writtenOrgMapping(tc.sso)); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want orgMapping, +got orgMapping:\n%s", tc.reason, diff)
+			}
+
+			pc := r.newConfig()
+			if err := kube.Get(context.Background(), tc.req, pc); err != nil {
+				t.Fatalf("kube.Get(...): unexpected error: %v", err)
+			}
+			status := newProviderConfig(pc).status
+			if status.OrgMappingSync == nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): expected the sync to be recorded", tc.reason)
+			}
+			if diff := cmp.Diff(tc.wantTenants, status.OrgMappingSync.Tenants); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want tenants, +got tenants:\n%s", tc.reason, diff)
+			}
+			if c := status.GetCondition(apisv1alpha1.TypeOrgMappingSynced); c.Status != corev1.ConditionTrue {
+				t.Errorf("\n%s\nr.Reconcile(...): OrgMappingSynced = %s, want True", tc.reason, c.Status)
+			}
+		})
+	}
+}
+
+func TestMappingReconcileGeneration(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.SetName("acme")
+	cr.SetUID("acme-uid")
+	cr.SetGeneration(1)
+	cr.Spec.ForProvider.ViewerGroups = []string{"acme-viewers"}
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+
+	pc := &apisv1alpha1.ClusterProviderConfig{}
+	pc.SetName("prod")
+
+	kube := newFakeKube(cr, pc)
+	sso := defaultMockSSO()
+	r := newMappingReconciler(kube, apisv1alpha1.ClusterProviderConfigKind, sso)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod"}}
+
+	generation := func() int64 {
+		t.Helper()
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("r.Reconcile(...): unexpected error: %v", err)
+		}
+		// Grafana now holds whatever was written.
+		if sso.putBody != nil {
+			sso.getResp = &sso_settings.GetProviderSettingsOK{Payload: &models.GetProviderSettingsOKBody{Settings: sso.putBody.Settings}}
+		}
+		got := &apisv1alpha1.ClusterProviderConfig{}
+		if err := kube.Get(context.Background(), req.NamespacedName, got); err != nil {
+			t.Fatalf("kube.Get(...): unexpected error: %v", err)
+		}
+		return got.Status.OrgMappingSync.Generation
+	}
+
+	if g := generation(); g != 1 {
+		t.Errorf("r.Reconcile(...): first sync: generation = %d, want 1", g)
+	}
+	if g := generation(); g != 1 || len(sso.putKeys) != 1 {
+		t.Errorf("r.Reconcile(...): unchanged sync: generation = %d after %d writes, want 1 after 1 write", g, len(sso.putKeys))
+	}
+
+	// A new generation of the Tenant that renders the same org_mapping is
+	// recorded without writing to Grafana.
+	cr.SetGeneration(2)
+	cr.Spec.ForProvider.Retention = v1alpha1.RetentionPolicy{Logs: "30d"}
+	if err := kube.Update(context.Background(), cr); err != nil {
+		t.Fatalf("kube.Update(...): unexpected error: %v", err)
+	}
+	if g := generation(); g != 2 || len(sso.putKeys) != 1 {
+		t.Errorf("r.Reconcile(...): new tenant generation: generation = %d after %d writes, want 2 after 1 write", g, len(sso.putKeys))
+	}
+}
+
+func TestMappingReconcileFailed(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.SetName("acme")
+	cr.SetUID("acme-uid")
+	cr.Spec.ForProvider.ViewerGroups = []string{"acme-viewers"}
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+
+	pc := &apisv1alpha1.ClusterProviderConfig{}
+	pc.SetName("prod")
+
+	kube := newFakeKube(cr, pc)
+	sso := defaultMockSSO()
+	sso.putErr = errors.New("boom")
+	r := newMappingReconciler(kube, apisv1alpha1.ClusterProviderConfigKind, sso)
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod"}}); err == nil {
+		t.Error("r.Reconcile(...): expected an error so that the request backs off")
+	}
+	got := &apisv1alpha1.ClusterProviderConfig{}
+	if err := kube.Get(context.Background(), client.ObjectKey{Name: "prod"}, got); err != nil {
+		t.Fatalf("kube.Get(...): unexpected error: %v", err)
+	}
+	if c := got.GetCondition(apisv1alpha1.TypeOrgMappingSynced); c.Status != corev1.ConditionFalse {
+		t.Errorf("r.Reconcile(...): OrgMappingSynced = %s, want False", c.Status)
+	}
+	if got.Status.OrgMappingSync != nil {
+		t.Errorf("r.Reconcile(...): recorded a failed sync: %+v", got.Status.OrgMappingSync)
+	}
+}
+
+func TestMappingReconcileDebounce(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.SetName("acme")
+	cr.Spec.ForProvider.ViewerGroups = []string{"acme-viewers"}
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+
+	pc := &apisv1alpha1.ClusterProviderConfig{}
+	pc.SetName("prod")
+
+	sso := defaultMockSSO()
+	r := newMappingReconciler(newFakeKube(cr, pc), apisv1alpha1.ClusterProviderConfigKind, sso)
+	r.debounce = newDebouncer(time.Minute)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod"}}
+	r.debounce.touch(req.NamespacedName)
+
+	got, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("r.Reconcile(...): unexpected error: %v", err)
+	}
+	if got.RequeueAfter <= 0 || got.RequeueAfter > time.Minute {
+		t.Errorf("r.Reconcile(...): RequeueAfter = %s, want the rest of the debounce window", got.RequeueAfter)
+	}
+	if sso.putBody != nil {
+		t.Error("r.Reconcile(...): wrote the org_mapping before the debounce window passed")
+	}
+}
+
+func TestDebouncer(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := newDebouncer(5 * time.Second)
+	d.now = func() time.Time { return now }
+	key := types.NamespacedName{Name: "prod"}
+
+	if got := d.wait(key); got != 0 {
+		t.Errorf("wait(...) before any change = %s, want 0", got)
+	}
+	d.touch(key)
+	now = now.Add(3 * time.Second)
+	if got := d.wait(key); got != 2*time.Second {
+		t.Errorf("wait(...) within the window = %s, want 2s", got)
+	}
+	// Another change restarts the window.
+	d.touch(key)
+	now = now.Add(3 * time.Second)
+	if got := d.wait(key); got != 2*time.Second {
+		t.Errorf("wait(...) within the restarted window = %s, want 2s", got)
+	}
+	now = now.Add(2 * time.Second)
+	if got := d.wait(key); got != 0 {
+		t.Errorf("wait(...) after the window = %s, want 0", got)
+	}
+	if len(d.deadlines) != 0 {
+		t.Errorf("wait(...): kept %d deadlines after the window passed, want 0", len(d.deadlines))
+	}
+}
+
+func TestOrgMappingSyncChanged(t *testing.T) {
+	withSync := func(generation int64, conditions ...xpv1.Condition) *apisv1alpha1.ClusterProviderConfig {
+		pc := &apisv1alpha1.ClusterProviderConfig{}
+		pc.SetName("prod")
+		if generation > 0 {
+			pc.Status.OrgMappingSync = &apisv1alpha1.OrgMappingSync{Generation: generation}
+		}
+		pc.Status.SetConditions(conditions...)
+		return pc
+	}
+
+	cases := map[string]struct {
+		reason string
+		old    client.Object
+		new    client.Object
+		want   bool
+	}{
+		"NewSync": {
+			reason: "Should pass an update recording another sync.",
+			old:    withSync(1, apisv1alpha1.OrgMappingSynced()),
+			new:    withSync(2, apisv1alpha1.OrgMappingSynced()),
+			want:   true,
+		},
+		"Failed": {
+			reason: "Should pass an update recording a failed sync.",
+			old:    withSync(1, apisv1alpha1.OrgMappingSynced()),
+			new:    withSync(1, apisv1alpha1.OrgMappingSyncFailed(errors.New("boom"))),
+			want:   true,
+		},
+		"OtherStatus": {
+			reason: "Should filter out updates that do not touch the org_mapping sync.",
+			old:    withSync(1, apisv1alpha1.OrgMappingSynced()),
+			new:    withSync(1, apisv1alpha1.OrgMappingSynced(), xpv1.Available()),
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := orgMappingSyncChanged().Update(ctrlevent.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})
+			if got != tc.want {
+				t.Errorf("\n%s\norgMappingSyncChanged().Update(...) = %v, want %v", tc.reason, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfigTenants(t *testing.T) {
+	tenant := func(ns, name, kind, pcName string) *v1alpha1.Tenant {
+		cr := tenantWithSpec(name, "1", nil, v1alpha1.RetentionPolicy{})
+		cr.SetNamespace(ns)
+		cr.SetName(name)
+		cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: kind, Name: pcName}
+		return cr
+	}
+	cluster := &v1alpha1.ClusterTenant{}
+	cluster.SetName("initech")
+	cluster.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Name: "prod"}
+	kube := newFakeKube(
+		tenant("team-a", "acme", apisv1alpha1.ClusterProviderConfigKind, "prod"),
+		tenant("team-a", "globex", apisv1alpha1.ProviderConfigKind, "prod"),
+		tenant("team-b", "hooli", apisv1alpha1.ClusterProviderConfigKind, "staging"),
+		cluster,
+	)
+
+	prod := &apisv1alpha1.ClusterProviderConfig{}
+	prod.SetName("prod")
+	nsProd := &apisv1alpha1.ProviderConfig{}
+	nsProd.SetNamespace("team-a")
+	nsProd.SetName("prod")
+
+	cases := map[string]struct {
+		reason  string
+		kind    string
+		cluster bool
+		config  client.Object
+		want    []reconcile.Request
+	}{
+		"Tenants": {
+			reason: "Should map a ClusterProviderConfig to the Tenants using it.",
+			kind:   apisv1alpha1.ClusterProviderConfigKind,
+			config: prod,
+			want:   []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "acme"}}},
+		},
+		"ClusterTenants": {
+			reason:  "Should map a ClusterProviderConfig to the ClusterTenants using it.",
+			kind:    apisv1alpha1.ClusterProviderConfigKind,
+			cluster: true,
+			config:  prod,
+			want:    []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "initech"}}},
+		},
+		"ProviderConfig": {
+			reason: "Should map a ProviderConfig to the Tenants in its namespace using it.",
+			kind:   apisv1alpha1.ProviderConfigKind,
+			config: nsProd,
+			want:   []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "globex"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := configTenants(kube, tc.kind, tc.cluster, logging.NewNopLogger())(context.Background(), tc.config)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nconfigTenants(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMappingReconcilePublish(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.SetName("acme")
+	cr.SetNamespace("team-a")
+	cr.SetUID("acme-uid")
+	cr.Spec.ForProvider.ViewerGroups = []string{"acme-viewers"}
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+
+	pc := &apisv1alpha1.ClusterProviderConfig{}
+	pc.SetName("prod")
+	pc.SetUID("prod-uid")
+
+	kube := newFakeKube(cr, pc)
+	sso := withSettings(map[string]any{"orgMapping": "break-glass:1:Admin"})
+	r := newMappingReconciler(kube, apisv1alpha1.ClusterProviderConfigKind, sso)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod"}}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("r.Reconcile(...): unexpected error: %v", err)
+	}
+	got := &apisv1alpha1.ClusterOrgMapping{}
+	if err := kube.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("kube.Get(...): unexpected error: %v", err)
+	}
+	if ref := metav1.GetControllerOf(got); ref == nil || ref.UID != "prod-uid" || ref.Kind != apisv1alpha1.ClusterProviderConfigKind {
+		t.Errorf("r.Reconcile(...): controller reference = %+v, want the ClusterProviderConfig", ref)
+	}
+	want := apisv1alpha1.OrgMappingStatus{
+		SyncGeneration: 1,
+		LastSyncTime:   got.Status.LastSyncTime,
+		Entries:        []string{"acme-viewers:1:Viewer"},
+		Tenants:        []apisv1alpha1.OrgMappingTenant{{Kind: v1alpha1.TenantKind, Namespace: "team-a", Name: "acme", TenantID: "acme", OrgID: "1"}},
+		Providers: []apisv1alpha1.OrgMappingProvider{{
+			Name:           grafana.DefaultSSOProvider,
+			Hash:           fmt.Sprintf("%x", sha256.Sum256([]byte("acme-viewers:1:Viewer"))),
+			ForeignEntries: []string{"break-glass:1:Admin"},
+		}},
+	}
+	if diff := cmp.Diff(want, got.Status); diff != "" {
+		t.Errorf("r.Reconcile(...): -want status, +got status:\n%s", diff)
+	}
+
+	// A failed sync keeps the last rendered org_mapping.
+	sso.getErr = errors.New("boom")
+	if _, err := r.Reconcile(context.Background(), req); err == nil {
+		t.Fatal("r.Reconcile(...): expected an error")
+	}
+	if err := kube.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("kube.Get(...): unexpected error: %v", err)
+	}
+	if got.Status.LastError == "" {
+		t.Error("r.Reconcile(...): expected the last error to be published")
+	}
+	if diff := cmp.Diff(want.Entries, got.Status.Entries); diff != "" {
+		t.Errorf("r.Reconcile(...): -want entries, +got entries:\n%s", diff)
+	}
+}
+
+func TestMappingReconcileProviders(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.SetName("acme")
+	cr.SetUID("acme-uid")
+	cr.Spec.ForProvider.ViewerGroups = []string{"acme-viewers"}
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+
+	pc := &apisv1alpha1.ClusterProviderConfig{}
+	pc.SetName("prod")
+	pc.Spec.SSOProviders = []apisv1alpha1.SSOProvider{apisv1alpha1.SSOProviderAzureAD, apisv1alpha1.SSOProviderOkta}
+
+	sso := defaultMockSSO()
+	r := newMappingReconciler(newFakeKube(cr, pc), apisv1alpha1.ClusterProviderConfigKind, sso)
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod"}}); err != nil {
+		t.Fatalf("r.Reconcile(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"azuread", "okta"}, sso.putKeys); diff != "" {
+		t.Errorf("r.Reconcile(...): -want providers, +got providers:\n%s", diff)
+	}
+}
+
+func TestMappingReconcileOrgAttributePath(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		current     map[string]any
+		ssoSettings *apisv1alpha1.SSOSettings
+		want        corev1.ConditionStatus
+	}{
+		"Missing": {
+			reason:  "Should warn on the ProviderConfig when Grafana would ignore the org_mapping.",
+			current: map[string]any{},
+			want:    corev1.ConditionFalse,
+		},
+		"SetInGrafana": {
+			reason:  "Should accept an orgAttributePath configured in Grafana.",
+			current: map[string]any{"orgAttributePath": "groups"},
+			want:    corev1.ConditionTrue,
+		},
+		"Managed": {
+			reason:      "Should accept an orgAttributePath managed through the ProviderConfig.",
+			current:     map[string]any{},
+			ssoSettings: &apisv1alpha1.SSOSettings{OrgAttributePath: ptr.To("groups")},
+			want:        corev1.ConditionTrue,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+			cr.SetName("acme")
+			cr.SetUID("acme-uid")
+			cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+
+			pc := &apisv1alpha1.ClusterProviderConfig{}
+			pc.SetName("prod")
+			pc.Spec.SSOSettings = tc.ssoSettings
+
+			kube := newFakeKube(cr, pc)
+			r := newMappingReconciler(kube, apisv1alpha1.ClusterProviderConfigKind, withSettings(tc.current))
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod"}}); err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): unexpected error: %v", tc.reason, err)
+			}
+
+			got := &apisv1alpha1.ClusterProviderConfig{}
+			if err := kube.Get(context.Background(), client.ObjectKey{Name: "prod"}, got); err != nil {
+				t.Fatalf("kube.Get(...): unexpected error: %v", err)
+			}
+			if c := got.GetCondition(apisv1alpha1.TypeOrgMappingEffective); c.Status != tc.want {
+				t.Errorf("\n%s\nr.Reconcile(...): OrgMappingEffective = %s, want %s", tc.reason, c.Status, tc.want)
+			}
+		})
+	}
+}
+
+func TestMappingReconcileOwned(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.SetName("acme")
+	cr.SetNamespace("team-a")
+	cr.SetUID("acme-uid")
+	cr.Spec.ForProvider.ViewerGroups = []string{"acme-viewers"}
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+
+	pc := &apisv1alpha1.ClusterProviderConfig{}
+	pc.SetName("prod")
+	pc.Spec.OrgMappingMode = apisv1alpha1.OrgMappingModeOwned
+	pc.Status.ManagedOrgMapping = []string{"acme-old:1:Viewer"}
+
+	sso := withSettings(map[string]any{"orgMapping": "break-glass:1:Admin,acme-old:1:Viewer"})
+	kube := newFakeKube(cr, pc)
+	r := newMappingReconciler(kube, apisv1alpha1.ClusterProviderConfigKind, sso)
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "prod"}}); err != nil {
+		t.Fatalf("r.Reconcile(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff("break-glass:1:Admin,acme-viewers:1:Viewer", writtenOrgMapping(sso)); diff != "" {
+		t.Errorf("r.Reconcile(...): -want orgMapping, +got orgMapping:\n%s", diff)
+	}
+
+	got := &apisv1alpha1.ClusterProviderConfig{}
+	if err := kube.Get(context.Background(), client.ObjectKey{Name: "prod"}, got); err != nil {
+		t.Fatalf("kube.Get(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"acme-viewers:1:Viewer"}, got.Status.ManagedOrgMapping); diff != "" {
+		t.Errorf("r.Reconcile(...): -want managed entries, +got managed entries:\n%s", diff)
+	}
+}
+
+func TestMappingDrifted(t *testing.T) {
+	expected := grafana.TenantEntries(grafana.TenantMapping{
+		OrgID:        "1",
+		ViewerGroups: []string{"viewers"},
+		EditorGroups: []string{"oidc:editors"},
+		AdminGroups:  []string{"admins"},
+	})
+	other := grafana.OrgMappingEntry{Group: "other", OrgID: "2", Role: grafana.RoleViewer}
+
+	cases := map[string]struct {
+		reason   string
+		current  map[string]any
+		settings map[string]any
+		expected []grafana.OrgMappingEntry
+		owned    map[string]bool
+		want     bool
+	}{
+		"InSync": {
+			reason:   "Should not report drift when Grafana holds exactly the expected entries in any order, including escaped colons.",
+			current:  map[string]any{"orgMapping": `other:2:Viewer,admins:1:Admin,viewers:1:Viewer,oidc\:editors:1:Editor`},
+			expected: append([]grafana.OrgMappingEntry{other}, expected...),
+			want:     false,
+		},
+		"RoleDowngraded": {
+			reason:   "Should report drift when a group's role differs.",
+			current:  map[string]any{"orgMapping": `viewers:1:Viewer,oidc\:editors:1:Editor,admins:1:Viewer`},
+			expected: expected,
+			want:     true,
+		},
+		"GroupMissing": {
+			reason:   "Should report drift when an expected group is missing.",
+			current:  map[string]any{"orgMapping": `viewers:1:Viewer,admins:1:Admin`},
+			expected: expected,
+			want:     true,
+		},
+		"StaleEntry": {
+			reason:   "Should report drift when Grafana holds an entry no Tenant expects.",
+			current:  map[string]any{"orgMapping": `viewers:1:Viewer,oidc\:editors:1:Editor,admins:1:Admin,removed:3:Editor`},
+			expected: expected,
+			want:     true,
+		},
+		"NotConfigured": {
+			reason:  "Should not report drift when SSO is not configured and no entries are expected.",
+			current: map[string]any{},
+			want:    false,
+		},
+		"OwnedIgnoresForeignEntries": {
+			reason:   "Should ignore entries the provider did not write in Owned mode.",
+			current:  map[string]any{"orgMapping": `break-glass:1:Admin,viewers:1:Viewer,oidc\:editors:1:Editor,admins:1:Admin`},
+			expected: expected,
+			owned:    map[string]bool{"viewers:1:Viewer": true, `oidc\:editors:1:Editor`: true, "admins:1:Admin": true},
+			want:     false,
+		},
+		"OwnedStaleEntry": {
+			reason:   "Should report drift when an entry the provider wrote is no longer expected in Owned mode.",
+			current:  map[string]any{"orgMapping": `viewers:1:Viewer,removed:1:Viewer`},
+			expected: expected[:1],
+			owned:    map[string]bool{"viewers:1:Viewer": true, "removed:1:Viewer": true},
+			want:     true,
+		},
+		"SettingsDrifted": {
+			reason:   "Should report drift when a managed SSO setting differs.",
+			current:  map[string]any{"orgMapping": `viewers:1:Viewer,oidc\:editors:1:Editor,admins:1:Admin`, "roleAttributePath": "'Viewer'"},
+			settings: map[string]any{"roleAttributePath": "contains(groups[*], 'admins') && 'Admin' || 'Viewer'"},
+			expected: expected,
+			want:     true,
+		},
+		"SettingsInSync": {
+			reason:   "Should not report drift when managed SSO settings match, even if Grafana returns booleans as strings.",
+			current:  map[string]any{"orgMapping": `viewers:1:Viewer,oidc\:editors:1:Editor,admins:1:Admin`, "skipOrgRoleSync": "false"},
+			settings: map[string]any{"skipOrgRoleSync": false},
+			expected: expected,
+			want:     false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := mappingDrifted(tc.current, tc.settings, tc.expected, tc.owned)
+			if got != tc.want {
+				t.Errorf("\n%s\nmappingDrifted(...): want %v, got %v", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOrgMappingCondition(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.SetUID("acme-uid")
+	cr.SetGeneration(2)
+
+	synced := func(tenants map[string]int64, conditions ...xpv1.Condition) *apisv1alpha1.ProviderConfigStatus {
+		s := &apisv1alpha1.ProviderConfigStatus{OrgMappingSync: &apisv1alpha1.OrgMappingSync{Generation: 7, Tenants: tenants}}
+		s.SetConditions(conditions...)
+		return s
+	}
+
+	cases := map[string]struct {
+		reason string
+		status *apisv1alpha1.ProviderConfigStatus
+		want   xpv1.Condition
+	}{
+		"Synced": {
+			reason: "Should report the sync generation that included the current generation of the Tenant.",
+			status: synced(map[string]int64{"acme-uid": 2}),
+			want:   v1alpha1.OrgMappingSynced(7, 2),
+		},
+		"OldGeneration": {
+			reason: "Should report a Tenant whose current generation was not synced yet as pending.",
+			status: synced(map[string]int64{"acme-uid": 1}, apisv1alpha1.OrgMappingSynced()),
+			want:   v1alpha1.OrgMappingPending(),
+		},
+		"NeverSynced": {
+			reason: "Should report a Tenant as pending before the first sync.",
+			status: &apisv1alpha1.ProviderConfigStatus{},
+			want:   v1alpha1.OrgMappingPending(),
+		},
+		"Failed": {
+			reason: "Should report the error of a failed sync that did not include the Tenant.",
+			status: synced(map[string]int64{}, apisv1alpha1.OrgMappingSyncFailed(errors.New("boom"))),
+			want:   v1alpha1.OrgMappingSyncFailed("boom"),
+		},
+		"FailedAfterSync": {
+			reason: "Should keep reporting a Tenant as synced when a later sync fails.",
+			status: synced(map[string]int64{"acme-uid": 2}, apisv1alpha1.OrgMappingSyncFailed(errors.New("boom"))),
+			want:   v1alpha1.OrgMappingSynced(7, 2),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := orgMappingCondition(cr, tc.status)
+			if diff := cmp.Diff(tc.want, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\norgMappingCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnqueueProviderConfig(t *testing.T) {
+	tenant := func(ns, name, kind, pcName string, groupSets ...string) *v1alpha1.Tenant {
+		cr := tenantWithSpec(name, "1", nil, v1alpha1.RetentionPolicy{})
+		cr.SetNamespace(ns)
+		cr.SetName(name)
+		cr.Spec.ForProvider.GroupSets = groupSets
+		cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: kind, Name: pcName}
+		return cr
+	}
+	acme := tenant("team-a", "acme", apisv1alpha1.ClusterProviderConfigKind, "prod", "platform")
+	globex := tenant("team-a", "globex", apisv1alpha1.ProviderConfigKind, "default", "platform")
+	initech := tenant("team-a", "initech", apisv1alpha1.ClusterProviderConfigKind, "staging")
+	kube := newFakeKube(acme, globex, initech)
+
+	gs := &v1alpha1.GroupSet{}
+	gs.SetNamespace("team-a")
+	gs.SetName("platform")
+
+	moved := acme.DeepCopy()
+	moved.Spec.ProviderConfigReference.Name = "staging"
+
+	cases := map[string]struct {
+		reason string
+		kind   string
+		send   func(e *enqueueProviderConfig, q workqueue.TypedRateLimitingInterface[reconcile.Request])
+		want   []reconcile.Request
+	}{
+		"Tenant": {
+			reason: "Should enqueue the ClusterProviderConfig of a Tenant.",
+			kind:   apisv1alpha1.ClusterProviderConfigKind,
+			send: func(e *enqueueProviderConfig, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				e.Create(context.Background(), ctrlevent.CreateEvent{Object: acme}, q)
+			},
+			want: []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "prod"}}},
+		},
+		"OtherKind": {
+			reason: "Should not enqueue a config of another kind.",
+			kind:   apisv1alpha1.ProviderConfigKind,
+			send: func(e *enqueueProviderConfig, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				e.Create(context.Background(), ctrlevent.CreateEvent{Object: acme}, q)
+			},
+		},
+		"Moved": {
+			reason: "Should enqueue both the old and the new config of a Tenant.",
+			kind:   apisv1alpha1.ClusterProviderConfigKind,
+			send: func(e *enqueueProviderConfig, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				e.Update(context.Background(), ctrlevent.UpdateEvent{ObjectOld: acme, ObjectNew: moved}, q)
+			},
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "prod"}},
+				{NamespacedName: types.NamespacedName{Name: "staging"}},
+			},
+		},
+		"GroupSet": {
+			reason: "Should enqueue the configs of the Tenants referencing a GroupSet.",
+			kind:   apisv1alpha1.ProviderConfigKind,
+			send: func(e *enqueueProviderConfig, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				e.Delete(context.Background(), ctrlevent.DeleteEvent{Object: gs}, q)
+			},
+			want: []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "default"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+			tc.send(&enqueueProviderConfig{kube: kube, kind: tc.kind, log: logging.NewNopLogger()}, q)
+
+			var got []reconcile.Request
+			for q.Len() > 0 {
+				req, _ := q.Get()
+				got = append(got, req)
+				q.Done(req)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nenqueue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnqueueProviderConfigDebounce(t *testing.T) {
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	e := &enqueueProviderConfig{kind: apisv1alpha1.ClusterProviderConfigKind, debounce: newDebouncer(100 * time.Millisecond), log: logging.NewNopLogger()}
+
+	for _, name := range []string{"a", "b", "c"} {
+		cr := tenantWithSpec(name, "1", nil, v1alpha1.RetentionPolicy{})
+		cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+		e.Create(context.Background(), ctrlevent.CreateEvent{Object: cr}, q)
+	}
+	if n := q.Len(); n != 0 {
+		t.Fatalf("enqueue(...): %d requests queued before the debounce window passed, want 0", n)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if n := q.Len(); n != 1 {
+		t.Errorf("enqueue(...): %d requests queued after the debounce window, want 1", n)
+	}
+}
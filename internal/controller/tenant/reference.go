@@ -0,0 +1,217 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource/unstructured/composed"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+)
+
+const (
+	errResolveReferences  = "cannot resolve references"
+	errResolveOrgID       = "cannot resolve spec.forProvider.orgId"
+	errResolveAdminGroups = "cannot resolve spec.forProvider.adminGroups"
+	errParseAPIVersion    = "cannot parse apiVersion"
+	errPatchReferences    = "cannot update managed resource with resolved references"
+)
+
+// referenceResolver resolves the references of a Tenant or ClusterTenant
+// before it is observed: those to GroupSets through the generated ResolveReferences
+// method, and those to managed resources of any kind, such as Grafana
+// Organizations of provider-grafana, through the unstructured client.
+type referenceResolver struct {
+	kube client.Client
+}
+
+func (r *referenceResolver) ResolveReferences(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(interface {
+		tenantObject
+		ResolveReferences(ctx context.Context, c client.Reader) error
+	})
+	if !ok {
+		return errors.New(errNotTenant)
+	}
+
+	existing := cr.DeepCopyObject().(tenantObject)
+	if err := cr.ResolveReferences(ctx, r.kube); err != nil {
+		return errors.Wrap(err, errResolveReferences)
+	}
+	if err := resolveOrgID(ctx, r.kube, cr); err != nil {
+		return errors.Wrap(err, errResolveOrgID)
+	}
+	if err := resolveAdminGroups(ctx, r.kube, cr); err != nil {
+		return errors.Wrap(err, errResolveAdminGroups)
+	}
+
+	if equality.Semantic.DeepEqual(existing.GetParameters(), cr.GetParameters()) {
+		return nil
+	}
+	return errors.Wrap(r.kube.Patch(ctx, cr, client.MergeFrom(existing)), errPatchReferences)
+}
+
+// resolveOrgID sets orgId from orgIdRef, or from the first resource matching
+// orgIdSelector.
+func resolveOrgID(ctx context.Context, kube client.Reader, cr tenantObject) error {
+	p := cr.GetParameters()
+	req := reference.NamespacedResolutionRequest{CurrentValue: p.OrgID}
+	var err error
+	switch {
+	case p.OrgIDRef != nil:
+		req.Reference = &p.OrgIDRef.NamespacedReference
+		req.To, req.Extract, err = target(p.OrgIDRef.APIVersion, p.OrgIDRef.Kind, p.OrgIDRef.FieldPath)
+	case p.OrgIDSelector != nil:
+		req.Selector = &p.OrgIDSelector.NamespacedSelector
+		req.To, req.Extract, err = target(p.OrgIDSelector.APIVersion, p.OrgIDSelector.Kind, p.OrgIDSelector.FieldPath)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rsp, err := reference.NewAPINamespacedResolver(kube, cr).Resolve(ctx, req)
+	if err != nil {
+		return err
+	}
+	p.OrgID = rsp.ResolvedValue
+	if p.OrgIDRef == nil && rsp.ResolvedReference != nil {
+		s := p.OrgIDSelector
+		p.OrgIDRef = &v1alpha1.TypedReference{APIVersion: s.APIVersion, Kind: s.Kind, FieldPath: s.FieldPath, NamespacedReference: *rsp.ResolvedReference}
+	}
+	return nil
+}
+
+// resolveAdminGroups sets adminGroups from adminGroupsRefs, which may each
+// reference a different kind, or from the resources matching
+// adminGroupsSelector.
+func resolveAdminGroups(ctx context.Context, kube client.Reader, cr tenantObject) error {
+	p := cr.GetParameters()
+	r := reference.NewAPINamespacedResolver(kube, cr)
+
+	if len(p.AdminGroupsRefs) > 0 {
+		groups := make([]string, 0, len(p.AdminGroupsRefs))
+		for i := range p.AdminGroupsRefs {
+			ref := &p.AdminGroupsRefs[i]
+			to, extract, err := target(ref.APIVersion, ref.Kind, ref.FieldPath)
+			if err != nil {
+				return err
+			}
+			rsp, err := r.Resolve(ctx, reference.NamespacedResolutionRequest{Reference: &ref.NamespacedReference, To: to, Extract: extract})
+			if err != nil {
+				return err
+			}
+			// Optional references that cannot be resolved yield no group.
+			if rsp.ResolvedValue != "" {
+				groups = append(groups, rsp.ResolvedValue)
+			}
+		}
+		p.AdminGroups = groups
+		return nil
+	}
+
+	s := p.AdminGroupsSelector
+	if s == nil {
+		return nil
+	}
+	to, extract, err := target(s.APIVersion, s.Kind, s.FieldPath)
+	if err != nil {
+		return err
+	}
+	rsp, err := r.ResolveMultiple(ctx, reference.MultiNamespacedResolutionRequest{CurrentValues: p.AdminGroups, Selector: &s.NamespacedSelector, To: to, Extract: extract})
+	if err != nil {
+		return err
+	}
+	p.AdminGroups = rsp.ResolvedValues
+	p.AdminGroupsRefs = make([]v1alpha1.TypedReference, 0, len(rsp.ResolvedReferences))
+	for _, ref := range rsp.ResolvedReferences {
+		p.AdminGroupsRefs = append(p.AdminGroupsRefs, v1alpha1.TypedReference{APIVersion: s.APIVersion, Kind: s.Kind, FieldPath: s.FieldPath, NamespacedReference: ref})
+	}
+	return nil
+}
+
+// target returns the resolution target for managed resources of the given
+// kind, and a function extracting the value at fieldPath, or their external
+// name if fieldPath is empty.
+func target(apiVersion, kind, fieldPath string) (reference.To, reference.ExtractValueFn, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return reference.To{}, nil, errors.Wrap(err, errParseAPIVersion)
+	}
+	mg := &unstructuredManaged{}
+	mg.SetGroupVersionKind(gv.WithKind(kind))
+	l := &unstructuredManagedList{}
+	l.SetGroupVersionKind(gv.WithKind(kind + "List"))
+
+	extract := reference.ExternalName()
+	if fieldPath != "" {
+		extract = func(mg resource.Managed) string {
+			u, ok := mg.(*unstructuredManaged)
+			if !ok {
+				return ""
+			}
+			v, err := fieldpath.Pave(u.Object).GetValue(fieldPath)
+			if err != nil || v == nil {
+				return ""
+			}
+			return fmt.Sprint(v)
+		}
+	}
+	return reference.To{Managed: mg, List: l}, extract, nil
+}
+
+// unstructuredManaged is a managed resource of a kind this provider has no Go
+// types for, such as one served by another provider.
+type unstructuredManaged struct {
+	composed.Unstructured
+}
+
+func (u *unstructuredManaged) GetManagementPolicies() xpv1.ManagementPolicies {
+	p := xpv1.ManagementPolicies{}
+	_ = fieldpath.Pave(u.Object).GetValueInto("spec.managementPolicies", &p)
+	return p
+}
+
+func (u *unstructuredManaged) SetManagementPolicies(p xpv1.ManagementPolicies) {
+	_ = fieldpath.Pave(u.Object).SetValue("spec.managementPolicies", p)
+}
+
+// unstructuredManagedList is a list of unstructuredManaged resources.
+type unstructuredManagedList struct {
+	unstructured.UnstructuredList
+}
+
+func (l *unstructuredManagedList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, 0, len(l.Items))
+	for i := range l.Items {
+		items = append(items, &unstructuredManaged{Unstructured: composed.Unstructured{Unstructured: l.Items[i]}})
+	}
+	return items
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+)
+
+var (
+	organizationGVK = schema.GroupVersionKind{Group: "oss.grafana.example.org", Version: "v1alpha1", Kind: "Organization"}
+	idpGroupGVK     = schema.GroupVersionKind{Group: "idp.example.org", Version: "v1alpha1", Kind: "Group"}
+)
+
+// foreign returns a managed resource of a kind served by another provider.
+func foreign(gvk schema.GroupVersionKind, name, externalName string, labels map[string]string, status map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{"status": status}}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace("team-a")
+	u.SetName(name)
+	u.SetLabels(labels)
+	meta.SetExternalName(u, externalName)
+	return u
+}
+
+func TestResolveReferences(t *testing.T) {
+	org := foreign(organizationGVK, "acme", "7", map[string]string{"tenant": "acme"}, map[string]any{"atProvider": map[string]any{"orgId": int64(7)}})
+	admins := foreign(idpGroupGVK, "admins", "b7e3c1d2", map[string]string{"role": "admin"}, nil)
+	leads := foreign(idpGroupGVK, "leads", "a1f09e44", map[string]string{"role": "admin"}, nil)
+
+	tenant := func(fn func(p *v1alpha1.TenantParameters)) *v1alpha1.Tenant {
+		cr := tenantWithSpec("acme", "", nil, v1alpha1.RetentionPolicy{})
+		cr.SetNamespace("team-a")
+		cr.SetName("acme")
+		fn(&cr.Spec.ForProvider)
+		return cr
+	}
+	orgRef := func(fieldPath string) *v1alpha1.TypedReference {
+		return &v1alpha1.TypedReference{APIVersion: "oss.grafana.example.org/v1alpha1", Kind: "Organization", FieldPath: fieldPath, NamespacedReference: xpv1.NamespacedReference{Name: "acme"}}
+	}
+	groupRef := func(name string) v1alpha1.TypedReference {
+		return v1alpha1.TypedReference{APIVersion: "idp.example.org/v1alpha1", Kind: "Group", NamespacedReference: xpv1.NamespacedReference{Name: name, Namespace: "team-a"}}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		cr      *v1alpha1.Tenant
+		want    v1alpha1.TenantParameters
+		wantErr bool
+	}{
+		"OrgIDRefFieldPath": {
+			reason: "Should set orgId from the field path of the referenced resource.",
+			cr:     tenant(func(p *v1alpha1.TenantParameters) { p.OrgIDRef = orgRef("status.atProvider.orgId") }),
+			want:   v1alpha1.TenantParameters{TenantID: "acme", OrgID: "7", OrgIDRef: orgRef("status.atProvider.orgId")},
+		},
+		"OrgIDSelector": {
+			reason: "Should set orgId and orgIdRef from the external name of the selected resource.",
+			cr: tenant(func(p *v1alpha1.TenantParameters) {
+				p.OrgIDSelector = &v1alpha1.TypedSelector{APIVersion: "oss.grafana.example.org/v1alpha1", Kind: "Organization", NamespacedSelector: xpv1.NamespacedSelector{MatchLabels: map[string]string{"tenant": "acme"}}}
+			}),
+			want: v1alpha1.TenantParameters{
+				TenantID:      "acme",
+				OrgID:         "7",
+				OrgIDRef:      &v1alpha1.TypedReference{APIVersion: "oss.grafana.example.org/v1alpha1", Kind: "Organization", NamespacedReference: xpv1.NamespacedReference{Name: "acme", Namespace: "team-a"}},
+				OrgIDSelector: &v1alpha1.TypedSelector{APIVersion: "oss.grafana.example.org/v1alpha1", Kind: "Organization", NamespacedSelector: xpv1.NamespacedSelector{MatchLabels: map[string]string{"tenant": "acme"}}},
+			},
+		},
+		"AdminGroupsRefs": {
+			reason: "Should replace adminGroups with the external names of the referenced resources, in order.",
+			cr: tenant(func(p *v1alpha1.TenantParameters) {
+				p.OrgID = "1"
+				p.AdminGroups = []string{"stale"}
+				p.AdminGroupsRefs = []v1alpha1.TypedReference{groupRef("leads"), groupRef("admins")}
+			}),
+			want: v1alpha1.TenantParameters{TenantID: "acme", OrgID: "1", AdminGroups: []string{"a1f09e44", "b7e3c1d2"}, AdminGroupsRefs: []v1alpha1.TypedReference{groupRef("leads"), groupRef("admins")}},
+		},
+		"AdminGroupsSelector": {
+			reason: "Should set adminGroups and adminGroupsRefs from the selected resources.",
+			cr: tenant(func(p *v1alpha1.TenantParameters) {
+				p.OrgID = "1"
+				p.AdminGroupsSelector = &v1alpha1.TypedSelector{APIVersion: "idp.example.org/v1alpha1", Kind: "Group", NamespacedSelector: xpv1.NamespacedSelector{MatchLabels: map[string]string{"role": "admin"}}}
+			}),
+			want: v1alpha1.TenantParameters{
+				TenantID:            "acme",
+				OrgID:               "1",
+				AdminGroups:         []string{"a1f09e44", "b7e3c1d2"},
+				AdminGroupsRefs:     []v1alpha1.TypedReference{groupRef("leads"), groupRef("admins")},
+				AdminGroupsSelector: &v1alpha1.TypedSelector{APIVersion: "idp.example.org/v1alpha1", Kind: "Group", NamespacedSelector: xpv1.NamespacedSelector{MatchLabels: map[string]string{"role": "admin"}}},
+			},
+		},
+		"MissingReference": {
+			reason: "Should return an error when a required reference cannot be resolved.",
+			cr: tenant(func(p *v1alpha1.TenantParameters) {
+				p.OrgIDRef = orgRef("")
+				p.OrgIDRef.Name = "missing"
+			}),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := newForeignFakeKube(tc.cr.DeepCopy(), org.DeepCopy(), admins.DeepCopy(), leads.DeepCopy())
+			r := &referenceResolver{kube: kube}
+
+			err := r.ResolveReferences(context.Background(), tc.cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\nr.ResolveReferences(...): error = %v, wantErr %v", tc.reason, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			got := &v1alpha1.Tenant{}
+			if err := kube.Get(context.Background(), client.ObjectKeyFromObject(tc.cr), got); err != nil {
+				t.Fatalf("kube.Get(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Spec.ForProvider, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nr.ResolveReferences(...): -want persisted forProvider, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// newForeignFakeKube returns a fake client that also serves the kinds of
+// other providers referenced by Tenants.
+func newForeignFakeKube(objs ...client.Object) client.Client {
+	scheme := kruntime.NewScheme()
+	_ = v1alpha1.SchemeBuilder.AddToScheme(scheme)
+	for _, gvk := range []schema.GroupVersionKind{organizationGVK, idpGroupGVK} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+	return clfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+	"github.com/loafoe/provider-orgmapper/internal/grafana"
+)
+
+const (
+	errGetConnSecret   = "cannot get connection secret"
+	errSyncSA          = "cannot sync Grafana service account"
+	errDeleteSA        = "cannot delete Grafana service account"
+	errMintToken       = "cannot mint Grafana service account token"
+	errDeleteOldTokens = "cannot delete expired Grafana service account tokens"
+)
+
+// Connection detail keys. The tokens of serviceAccounts are published under
+// keyToken followed by a dot and the name of the service account.
+const (
+	keyTenantID   = "tenantId"
+	keyOrgID      = "orgId"
+	keyGrafanaURL = "grafanaUrl"
+	keyToken      = "token"
+)
+
+// A token is rotated when less than 1/rotateDivisor of its TTL remains.
+const rotateDivisor = 3
+
+// serviceAccount is a Grafana service account wanted for a Tenant, along with
+// the connection detail key its token is published under.
+type serviceAccount struct {
+	key  string
+	name string
+	role string
+	ttl  time.Duration
+}
+
+// connectionServiceAccountOf returns the connection service account of a
+// Tenant with its defaults applied, or nil if it has none.
+func connectionServiceAccountOf(cr tenantObject) *serviceAccount {
+	sa := cr.GetParameters().ConnectionServiceAccount
+	if sa == nil {
+		return nil
+	}
+	want := &serviceAccount{key: keyToken, name: sa.Name, role: sa.Role}
+	if want.name == "" {
+		want.name = "orgmapper-" + cr.GetParameters().TenantID
+	}
+	if want.role == "" {
+		want.role = grafana.RoleViewer
+	}
+	return want
+}
+
+// listedServiceAccountsOf returns the serviceAccounts of a Tenant with their
+// defaults applied.
+func listedServiceAccountsOf(cr tenantObject) []serviceAccount {
+	listed := cr.GetParameters().ServiceAccounts
+	wants := make([]serviceAccount, 0, len(listed))
+	for _, sa := range listed {
+		want := serviceAccount{key: keyToken + "." + sa.Name, name: sa.Name, role: sa.Role}
+		if want.role == "" {
+			want.role = grafana.RoleViewer
+		}
+		if sa.TokenTTL != nil {
+			want.ttl = sa.TokenTTL.Duration
+		}
+		wants = append(wants, want)
+	}
+	return wants
+}
+
+// serviceAccountsOf returns all service accounts wanted for a Tenant.
+func serviceAccountsOf(cr tenantObject) []serviceAccount {
+	wants := listedServiceAccountsOf(cr)
+	if sa := connectionServiceAccountOf(cr); sa != nil {
+		wants = append([]serviceAccount{*sa}, wants...)
+	}
+	return wants
+}
+
+// observedServiceAccount returns the service account observed for want, if
+// any.
+func observedServiceAccount(cr tenantObject, want serviceAccount) *v1alpha1.ServiceAccountObservation {
+	obs := cr.GetObservation()
+	if want.key == keyToken {
+		return obs.ConnectionServiceAccount
+	}
+	for i := range obs.ServiceAccounts {
+		if obs.ServiceAccounts[i].Name == want.name {
+			return &obs.ServiceAccounts[i]
+		}
+	}
+	return nil
+}
+
+// serviceAccountsUpToDate reports whether exactly the service accounts wanted
+// for a Tenant exist in its org with the wanted roles.
+func serviceAccountsUpToDate(cr tenantObject) bool {
+	obs := cr.GetObservation()
+	wants := serviceAccountsOf(cr)
+	observed := len(obs.ServiceAccounts)
+	if obs.ConnectionServiceAccount != nil {
+		observed++
+	}
+	if observed != len(wants) {
+		return false
+	}
+	for _, want := range wants {
+		o := observedServiceAccount(cr, want)
+		if o == nil || o.Name != want.name || o.Role != want.role || o.OrgID != orgIDOf(cr) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokensDue reports whether a token of a service account wanted for a Tenant
+// is missing from the published tokens or due for rotation.
+func tokensDue(cr tenantObject, published map[string][]byte) bool {
+	for _, want := range serviceAccountsOf(cr) {
+		if len(published[want.key]) == 0 {
+			return true
+		}
+		if o := observedServiceAccount(cr, want); o != nil && rotationDue(o, want.ttl) {
+			return true
+		}
+	}
+	return false
+}
+
+// rotationDue reports whether the published token of a service account must
+// be replaced: because less than a third of its TTL remains, or because it was
+// minted with another expiry than the TTL now asks for.
+func rotationDue(o *v1alpha1.ServiceAccountObservation, ttl time.Duration) bool {
+	if ttl == 0 {
+		return o.TokenExpiresAt != nil
+	}
+	return o.TokenExpiresAt == nil || time.Until(o.TokenExpiresAt.Time) < ttl/rotateDivisor
+}
+
+// connectionDetails returns the details published to the connection secret
+// of a Tenant: the tenant ID to send in the X-Scope-OrgID header, the ID of its
+// Grafana organization, the Grafana URL and the tokens of its service
+// accounts.
+func (c *external) connectionDetails(cr tenantObject, tokens map[string][]byte) managed.ConnectionDetails {
+	cd := managed.ConnectionDetails{
+		keyTenantID:   []byte(cr.GetParameters().TenantID),
+		keyOrgID:      []byte(orgIDOf(cr)),
+		keyGrafanaURL: []byte(c.pc.spec.GrafanaURL),
+	}
+	for k, v := range tokens {
+		if len(v) > 0 {
+			cd[k] = v
+		}
+	}
+	return cd
+}
+
+// publishedTokens returns the service account tokens in the connection
+// secret of a Tenant, keyed as in the connection details.
+func (c *external) publishedTokens(ctx context.Context, cr tenantObject) (map[string][]byte, error) {
+	ref := cr.GetWriteConnectionSecretToReference()
+	wants := serviceAccountsOf(cr)
+	if ref == nil || len(wants) == 0 {
+		return nil, nil
+	}
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, client.ObjectKey{Namespace: cr.GetNamespace(), Name: ref.Name}, s); err != nil {
+		return nil, errors.Wrap(client.IgnoreNotFound(err), errGetConnSecret)
+	}
+	tokens := make(map[string][]byte, len(wants))
+	for _, want := range wants {
+		if v, ok := s.Data[want.key]; ok {
+			tokens[want.key] = v
+		}
+	}
+	return tokens, nil
+}
+
+// mintedTokens reports whether tokens holds service account tokens that are
+// not published to the connection secret of a Tenant yet.
+func (c *external) mintedTokens(ctx context.Context, cr tenantObject, tokens map[string][]byte) bool {
+	published, err := c.publishedTokens(ctx, cr)
+	if err != nil {
+		// Err on the side of publishing.
+		return true
+	}
+	for k, v := range tokens {
+		if len(v) > 0 && !bytes.Equal(published[k], v) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncServiceAccounts makes sure the service accounts wanted for a Tenant
+// exist in its org, recording them in status, and deletes those no longer
+// wanted. It returns the tokens to publish: the published ones, and new ones
+// for service accounts without a token or whose token is due for rotation.
+// A failure to sync one service account does not stop the others; the first
+// error is returned along with the tokens.
+func (c *external) syncServiceAccounts(ctx context.Context, cr tenantObject) (map[string][]byte, error) {
+	published, err := c.publishedTokens(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	obs := cr.GetObservation()
+	tokens := map[string][]byte{}
+	var first error
+	record := func(err error) {
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+
+	var token []byte
+	want := connectionServiceAccountOf(cr)
+	obs.ConnectionServiceAccount, token, err = c.syncServiceAccount(cr, want, obs.ConnectionServiceAccount, published[keyToken])
+	record(err)
+	tokens[keyToken] = token
+
+	previous := make(map[string]*v1alpha1.ServiceAccountObservation, len(obs.ServiceAccounts))
+	for i := range obs.ServiceAccounts {
+		previous[obs.ServiceAccounts[i].Name] = obs.ServiceAccounts[i].DeepCopy()
+	}
+	observed := make([]v1alpha1.ServiceAccountObservation, 0, len(cr.GetParameters().ServiceAccounts))
+	for _, want := range listedServiceAccountsOf(cr) {
+		o, token, err := c.syncServiceAccount(cr, &want, previous[want.name], published[want.key])
+		record(err)
+		delete(previous, want.name)
+		if o != nil {
+			observed = append(observed, *o)
+		}
+		tokens[want.key] = token
+	}
+	// Delete the service accounts no longer listed in the order they were
+	// observed, so that the status lists those that failed in a stable order.
+	for i := range obs.ServiceAccounts {
+		prev, ok := previous[obs.ServiceAccounts[i].Name]
+		if !ok {
+			continue
+		}
+		delete(previous, prev.Name)
+		if o, _, err := c.syncServiceAccount(cr, nil, prev, nil); err != nil {
+			record(err)
+			observed = append(observed, *o)
+		}
+	}
+	obs.ServiceAccounts = observed
+	if len(obs.ServiceAccounts) == 0 {
+		obs.ServiceAccounts = nil
+	}
+
+	return tokens, first
+}
+
+// syncServiceAccount makes sure the service account want exists in the org of
+// a Tenant. The previously observed service account prev is deleted when it
+// is no longer wanted, or was created in another org or under another name.
+// It returns the observed service account and its token: the published one,
+// unless it belongs to another service account or is due for rotation, in
+// which case a new token is minted. On error, whatever remains of prev and its
+// token are returned.
+func (c *external) syncServiceAccount(cr tenantObject, want *serviceAccount, prev *v1alpha1.ServiceAccountObservation, token []byte) (*v1alpha1.ServiceAccountObservation, []byte, error) {
+	orgID := orgIDOf(cr)
+	if prev != nil && (want == nil || prev.OrgID != orgID || prev.Name != want.name) {
+		if err := grafana.DeleteServiceAccount(c.accounts, prev.OrgID, prev.ID); err != nil {
+			return prev, token, errors.Wrap(err, errDeleteSA)
+		}
+		prev, token = nil, nil
+	}
+	if want == nil {
+		return nil, nil, nil
+	}
+
+	id, err := grafana.EnsureServiceAccount(c.accounts, orgID, want.name, want.role)
+	if err != nil {
+		return prev, token, errors.Wrap(err, errSyncSA)
+	}
+	o := &v1alpha1.ServiceAccountObservation{ID: id, OrgID: orgID, Name: want.name, Role: want.role}
+
+	// A token published for another service account is of no use.
+	if prev == nil || prev.ID != id {
+		token = nil
+	}
+	if len(token) > 0 {
+		o.TokenExpiresAt = prev.TokenExpiresAt
+		if !rotationDue(prev, want.ttl) {
+			return o, token, nil
+		}
+	}
+
+	// Grafana keeps expired tokens, such as those replaced by earlier
+	// rotations, until they are deleted.
+	if err := grafana.DeleteExpiredTokens(c.accounts, orgID, id); err != nil {
+		return o, token, errors.Wrap(err, errDeleteOldTokens)
+	}
+	now := time.Now()
+	key, err := grafana.CreateServiceAccountToken(c.accounts, orgID, id, fmt.Sprintf("%s-%d", want.name, now.Unix()), want.ttl)
+	if err != nil {
+		return o, token, errors.Wrap(err, errMintToken)
+	}
+	o.TokenExpiresAt = nil
+	if want.ttl > 0 {
+		o.TokenExpiresAt = &metav1.Time{Time: now.Add(want.ttl).UTC().Truncate(time.Second)}
+	}
+	return o, []byte(key), nil
+}
+
+// deleteServiceAccounts deletes the service accounts recorded in the status
+// of a Tenant, along with their tokens.
+func (c *external) deleteServiceAccounts(cr tenantObject) error {
+	obs := cr.GetObservation()
+	if sa := obs.ConnectionServiceAccount; sa != nil {
+		if err := grafana.DeleteServiceAccount(c.accounts, sa.OrgID, sa.ID); err != nil {
+			return errors.Wrap(err, errDeleteSA)
+		}
+		obs.ConnectionServiceAccount = nil
+	}
+	for len(obs.ServiceAccounts) > 0 {
+		sa := obs.ServiceAccounts[0]
+		if err := grafana.DeleteServiceAccount(c.accounts, sa.OrgID, sa.ID); err != nil {
+			return errors.Wrap(err, errDeleteSA)
+		}
+		obs.ServiceAccounts = obs.ServiceAccounts[1:]
+	}
+	obs.ServiceAccounts = nil
+	return nil
+}
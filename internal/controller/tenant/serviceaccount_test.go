@@ -0,0 +1,355 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+
+	"github.com/grafana/grafana-openapi-client-go/client/service_accounts"
+	"github.com/grafana/grafana-openapi-client-go/models"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+	"github.com/loafoe/provider-orgmapper/internal/grafana"
+)
+
+// mockAccounts implements grafana.ServiceAccountClient for controller tests,
+// backed by a list of service accounts. The org a call is scoped to is ignored.
+type mockAccounts struct {
+	accounts []*models.ServiceAccountDTO
+	tokens   []string
+	deleted  []int64
+}
+
+func (m *mockAccounts) SearchOrgServiceAccountsWithPaging(_ *service_accounts.SearchOrgServiceAccountsWithPagingParams, _ ...service_accounts.ClientOption) (*service_accounts.SearchOrgServiceAccountsWithPagingOK, error) {
+	return &service_accounts.SearchOrgServiceAccountsWithPagingOK{Payload: &models.SearchOrgServiceAccountsResult{ServiceAccounts: m.accounts}}, nil
+}
+
+func (m *mockAccounts) CreateServiceAccount(params *service_accounts.CreateServiceAccountParams, _ ...service_accounts.ClientOption) (*service_accounts.CreateServiceAccountCreated, error) {
+	sa := &models.ServiceAccountDTO{ID: int64(len(m.accounts) + len(m.deleted) + 1), Name: params.Body.Name, Role: params.Body.Role}
+	m.accounts = append(m.accounts, sa)
+	return &service_accounts.CreateServiceAccountCreated{Payload: sa}, nil
+}
+
+func (m *mockAccounts) UpdateServiceAccount(_ *service_accounts.UpdateServiceAccountParams, _ ...service_accounts.ClientOption) (*service_accounts.UpdateServiceAccountOK, error) {
+	return &service_accounts.UpdateServiceAccountOK{}, nil
+}
+
+func (m *mockAccounts) DeleteServiceAccount(id int64, _ ...service_accounts.ClientOption) (*service_accounts.DeleteServiceAccountOK, error) {
+	m.accounts = slices.DeleteFunc(m.accounts, func(sa *models.ServiceAccountDTO) bool { return sa.ID == id })
+	m.deleted = append(m.deleted, id)
+	return &service_accounts.DeleteServiceAccountOK{}, nil
+}
+
+func (m *mockAccounts) CreateToken(params *service_accounts.CreateTokenParams, _ ...service_accounts.ClientOption) (*service_accounts.CreateTokenOK, error) {
+	m.tokens = append(m.tokens, params.Body.Name)
+	return &service_accounts.CreateTokenOK{Payload: &models.NewAPIKeyResult{Key: "minted"}}, nil
+}
+
+func (m *mockAccounts) ListTokens(_ int64, _ ...service_accounts.ClientOption) (*service_accounts.ListTokensOK, error) {
+	return &service_accounts.ListTokensOK{}, nil
+}
+
+func (m *mockAccounts) DeleteToken(_, _ int64, _ ...service_accounts.ClientOption) (*service_accounts.DeleteTokenOK, error) {
+	return &service_accounts.DeleteTokenOK{}, nil
+}
+
+func TestSyncServiceAccounts(t *testing.T) {
+	secret := func(data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{Data: map[string][]byte{}}
+		for k, v := range data {
+			s.Data[k] = []byte(v)
+		}
+		s.SetNamespace("team-a")
+		s.SetName("acme-conn")
+		return s
+	}
+	in := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: time.Now().Add(d)}
+	}
+
+	cases := map[string]struct {
+		reason      string
+		orgID       string
+		conn        *v1alpha1.ConnectionServiceAccount
+		listed      []v1alpha1.ServiceAccount
+		observed    *v1alpha1.ServiceAccountObservation
+		observedSAs []v1alpha1.ServiceAccountObservation
+		accounts    []*models.ServiceAccountDTO
+		secret      *corev1.Secret
+		want        map[string]string
+		wantObs     *v1alpha1.ServiceAccountObservation
+		wantSAs     []v1alpha1.ServiceAccountObservation
+		wantDeleted []int64
+		wantMinted  int
+	}{
+		"Mint": {
+			reason:     "Should create the connection service account and mint a token when none is published.",
+			orgID:      "1",
+			conn:       &v1alpha1.ConnectionServiceAccount{},
+			want:       map[string]string{keyToken: "minted"},
+			wantObs:    &v1alpha1.ServiceAccountObservation{ID: 1, OrgID: "1", Name: "orgmapper-acme", Role: grafana.RoleViewer},
+			wantMinted: 1,
+		},
+		"KeepPublished": {
+			reason:   "Should keep the published token of an existing service account.",
+			orgID:    "1",
+			conn:     &v1alpha1.ConnectionServiceAccount{Name: "ci", Role: grafana.RoleEditor},
+			observed: &v1alpha1.ServiceAccountObservation{ID: 1, OrgID: "1", Name: "ci", Role: grafana.RoleViewer},
+			accounts: []*models.ServiceAccountDTO{{ID: 1, Name: "ci", Role: grafana.RoleViewer}},
+			secret:   secret(map[string]string{keyToken: "published"}),
+			want:     map[string]string{keyToken: "published"},
+			wantObs:  &v1alpha1.ServiceAccountObservation{ID: 1, OrgID: "1", Name: "ci", Role: grafana.RoleEditor},
+		},
+		"MovedOrg": {
+			reason:      "Should replace the service account of the previous org and mint a new token.",
+			orgID:       "2",
+			conn:        &v1alpha1.ConnectionServiceAccount{Name: "ci"},
+			observed:    &v1alpha1.ServiceAccountObservation{ID: 1, OrgID: "1", Name: "ci", Role: grafana.RoleViewer},
+			accounts:    []*models.ServiceAccountDTO{{ID: 1, Name: "ci", Role: grafana.RoleViewer}},
+			secret:      secret(map[string]string{keyToken: "published"}),
+			want:        map[string]string{keyToken: "minted"},
+			wantObs:     &v1alpha1.ServiceAccountObservation{ID: 2, OrgID: "2", Name: "ci", Role: grafana.RoleViewer},
+			wantDeleted: []int64{1},
+			wantMinted:  1,
+		},
+		"Removed": {
+			reason:   "Should delete service accounts that are no longer wanted, in the order they were observed.",
+			orgID:    "1",
+			observed: &v1alpha1.ServiceAccountObservation{ID: 1, OrgID: "1", Name: "ci", Role: grafana.RoleViewer},
+			observedSAs: []v1alpha1.ServiceAccountObservation{
+				{ID: 2, OrgID: "1", Name: "deploy", Role: grafana.RoleEditor},
+				{ID: 3, OrgID: "1", Name: "ingest", Role: grafana.RoleViewer},
+				{ID: 4, OrgID: "1", Name: "backup", Role: grafana.RoleViewer},
+			},
+			accounts:    []*models.ServiceAccountDTO{{ID: 1, Name: "ci"}, {ID: 2, Name: "deploy"}, {ID: 3, Name: "ingest"}, {ID: 4, Name: "backup"}},
+			want:        map[string]string{},
+			wantDeleted: []int64{1, 2, 3, 4},
+		},
+		"Listed": {
+			reason: "Should create listed service accounts and publish their tokens under their own keys.",
+			orgID:  "1",
+			listed: []v1alpha1.ServiceAccount{
+				{Name: "deploy", Role: grafana.RoleEditor},
+				{Name: "ingest", TokenTTL: &metav1.Duration{Duration: 24 * time.Hour}},
+			},
+			observedSAs: []v1alpha1.ServiceAccountObservation{{ID: 1, OrgID: "1", Name: "deploy", Role: grafana.RoleEditor}},
+			accounts:    []*models.ServiceAccountDTO{{ID: 1, Name: "deploy", Role: grafana.RoleEditor}},
+			secret:      secret(map[string]string{"token.deploy": "published"}),
+			want:        map[string]string{"token.deploy": "published", "token.ingest": "minted"},
+			wantSAs: []v1alpha1.ServiceAccountObservation{
+				{ID: 1, OrgID: "1", Name: "deploy", Role: grafana.RoleEditor},
+				{ID: 2, OrgID: "1", Name: "ingest", Role: grafana.RoleViewer, TokenExpiresAt: in(24 * time.Hour)},
+			},
+			wantMinted: 1,
+		},
+		"RotateBeforeExpiry": {
+			reason:      "Should mint a new token when less than a third of the TTL remains.",
+			orgID:       "1",
+			listed:      []v1alpha1.ServiceAccount{{Name: "ingest", TokenTTL: &metav1.Duration{Duration: 24 * time.Hour}}},
+			observedSAs: []v1alpha1.ServiceAccountObservation{{ID: 1, OrgID: "1", Name: "ingest", Role: grafana.RoleViewer, TokenExpiresAt: in(time.Hour)}},
+			accounts:    []*models.ServiceAccountDTO{{ID: 1, Name: "ingest", Role: grafana.RoleViewer}},
+			secret:      secret(map[string]string{"token.ingest": "published"}),
+			want:        map[string]string{"token.ingest": "minted"},
+			wantSAs:     []v1alpha1.ServiceAccountObservation{{ID: 1, OrgID: "1", Name: "ingest", Role: grafana.RoleViewer, TokenExpiresAt: in(24 * time.Hour)}},
+			wantMinted:  1,
+		},
+		"NotYetDue": {
+			reason:      "Should keep a token with more than a third of its TTL remaining.",
+			orgID:       "1",
+			listed:      []v1alpha1.ServiceAccount{{Name: "ingest", TokenTTL: &metav1.Duration{Duration: 24 * time.Hour}}},
+			observedSAs: []v1alpha1.ServiceAccountObservation{{ID: 1, OrgID: "1", Name: "ingest", Role: grafana.RoleViewer, TokenExpiresAt: in(20 * time.Hour)}},
+			accounts:    []*models.ServiceAccountDTO{{ID: 1, Name: "ingest", Role: grafana.RoleViewer}},
+			secret:      secret(map[string]string{"token.ingest": "published"}),
+			want:        map[string]string{"token.ingest": "published"},
+			wantSAs:     []v1alpha1.ServiceAccountObservation{{ID: 1, OrgID: "1", Name: "ingest", Role: grafana.RoleViewer, TokenExpiresAt: in(20 * time.Hour)}},
+		},
+	}
+
+	// Expiry timestamps are computed from the current time.
+	approxTime := cmp.Comparer(func(a, b *metav1.Time) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return a.Sub(b.Time).Abs() < time.Minute
+	})
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tenantWithSpec("acme", tc.orgID, nil, v1alpha1.RetentionPolicy{})
+			cr.SetNamespace("team-a")
+			cr.Spec.ForProvider.ConnectionServiceAccount = tc.conn
+			cr.Spec.ForProvider.ServiceAccounts = tc.listed
+			cr.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: "acme-conn"}
+			cr.Status.AtProvider.ConnectionServiceAccount = tc.observed
+			cr.Status.AtProvider.ServiceAccounts = tc.observedSAs
+
+			var objs []client.Object
+			if tc.secret != nil {
+				objs = append(objs, tc.secret)
+			}
+			m := &mockAccounts{accounts: tc.accounts}
+			e := external{pc: testProviderConfig(), kube: newFakeKube(objs...), accounts: m, logger: logging.NewNopLogger()}
+
+			tokens, err := e.syncServiceAccounts(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.syncServiceAccounts(...): unexpected error: %v", tc.reason, err)
+			}
+			got := map[string]string{}
+			for k, v := range e.connectionDetails(cr, tokens) {
+				if k != keyTenantID && k != keyOrgID && k != keyGrafanaURL {
+					got[k] = string(v)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.syncServiceAccounts(...): -want tokens, +got tokens:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantObs, cr.Status.AtProvider.ConnectionServiceAccount, approxTime); diff != "" {
+				t.Errorf("\n%s\ne.syncServiceAccounts(...): -want connection status, +got connection status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantSAs, cr.Status.AtProvider.ServiceAccounts, approxTime); diff != "" {
+				t.Errorf("\n%s\ne.syncServiceAccounts(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, m.deleted); diff != "" {
+				t.Errorf("\n%s\ne.syncServiceAccounts(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+			if len(m.tokens) != tc.wantMinted {
+				t.Errorf("\n%s\ne.syncServiceAccounts(...): minted %d tokens, want %d", tc.reason, len(m.tokens), tc.wantMinted)
+			}
+		})
+	}
+}
+
+func TestMintedTokens(t *testing.T) {
+	published := &corev1.Secret{Data: map[string][]byte{keyToken: []byte("published")}}
+	published.SetNamespace("team-a")
+	published.SetName("acme-conn")
+
+	cases := map[string]struct {
+		reason string
+		tokens map[string][]byte
+		want   bool
+	}{
+		"Published": {
+			reason: "Should report no new tokens when all tokens are published.",
+			tokens: map[string][]byte{keyToken: []byte("published")},
+		},
+		"Minted": {
+			reason: "Should report a token that differs from the published one.",
+			tokens: map[string][]byte{keyToken: []byte("minted")},
+			want:   true,
+		},
+		"Failed": {
+			reason: "Should ignore service accounts that have no token.",
+			tokens: map[string][]byte{keyToken: nil},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+			cr.SetNamespace("team-a")
+			cr.Spec.ForProvider.ConnectionServiceAccount = &v1alpha1.ConnectionServiceAccount{}
+			cr.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: "acme-conn"}
+
+			e := external{pc: testProviderConfig(), kube: newFakeKube(published), logger: logging.NewNopLogger()}
+			if got := e.mintedTokens(context.Background(), cr, tc.tokens); got != tc.want {
+				t.Errorf("\n%s\ne.mintedTokens(...): want %v, got %v", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTokensDue(t *testing.T) {
+	ttl := &metav1.Duration{Duration: 24 * time.Hour}
+	cases := map[string]struct {
+		reason    string
+		listed    []v1alpha1.ServiceAccount
+		observed  []v1alpha1.ServiceAccountObservation
+		published map[string][]byte
+		want      bool
+	}{
+		"NoServiceAccounts": {
+			reason: "Should not be due without service accounts.",
+		},
+		"Missing": {
+			reason:   "Should be due when a token is missing from the connection secret.",
+			listed:   []v1alpha1.ServiceAccount{{Name: "deploy"}},
+			observed: []v1alpha1.ServiceAccountObservation{{Name: "deploy"}},
+			want:     true,
+		},
+		"Expiring": {
+			reason:    "Should be due when a token expires within a third of its TTL.",
+			listed:    []v1alpha1.ServiceAccount{{Name: "deploy", TokenTTL: ttl}},
+			observed:  []v1alpha1.ServiceAccountObservation{{Name: "deploy", TokenExpiresAt: &metav1.Time{Time: time.Now().Add(time.Hour)}}},
+			published: map[string][]byte{"token.deploy": []byte("published")},
+			want:      true,
+		},
+		"TTLRemoved": {
+			reason:    "Should be due when a token expires although the TTL was removed.",
+			listed:    []v1alpha1.ServiceAccount{{Name: "deploy"}},
+			observed:  []v1alpha1.ServiceAccountObservation{{Name: "deploy", TokenExpiresAt: &metav1.Time{Time: time.Now().Add(20 * time.Hour)}}},
+			published: map[string][]byte{"token.deploy": []byte("published")},
+			want:      true,
+		},
+		"Valid": {
+			reason:    "Should not be due when the published token is valid for long enough.",
+			listed:    []v1alpha1.ServiceAccount{{Name: "deploy", TokenTTL: ttl}},
+			observed:  []v1alpha1.ServiceAccountObservation{{Name: "deploy", TokenExpiresAt: &metav1.Time{Time: time.Now().Add(20 * time.Hour)}}},
+			published: map[string][]byte{"token.deploy": []byte("published")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+			cr.Spec.ForProvider.ServiceAccounts = tc.listed
+			cr.Status.AtProvider.ServiceAccounts = tc.observed
+			if got := tokensDue(cr, tc.published); got != tc.want {
+				t.Errorf("\n%s\ntokensDue(...): got %v, want %v", tc.reason, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeleteServiceAccounts(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.Status.AtProvider.ConnectionServiceAccount = &v1alpha1.ServiceAccountObservation{ID: 1, OrgID: "1", Name: "orgmapper-acme"}
+	cr.Status.AtProvider.ServiceAccounts = []v1alpha1.ServiceAccountObservation{{ID: 2, OrgID: "1", Name: "deploy"}}
+	m := &mockAccounts{accounts: []*models.ServiceAccountDTO{{ID: 1}, {ID: 2}}}
+	e := external{pc: testProviderConfig(), accounts: m, logger: logging.NewNopLogger()}
+
+	if err := e.deleteServiceAccounts(cr); err != nil {
+		t.Fatalf("e.deleteServiceAccounts(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int64{1, 2}, m.deleted); diff != "" {
+		t.Errorf("e.deleteServiceAccounts(...): -want deleted, +got deleted:\n%s", diff)
+	}
+	if cr.Status.AtProvider.ConnectionServiceAccount != nil || cr.Status.AtProvider.ServiceAccounts != nil {
+		t.Errorf("e.deleteServiceAccounts(...): expected the service accounts to be removed from status")
+	}
+}
@@ -18,13 +18,23 @@ package tenant
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
@@ -38,32 +48,117 @@ import (
 
 	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
 	apisv1alpha1 "github.com/loafoe/provider-orgmapper/apis/v1alpha1"
+	"github.com/loafoe/provider-orgmapper/internal/clients"
 	"github.com/loafoe/provider-orgmapper/internal/grafana"
+	"github.com/loafoe/provider-orgmapper/internal/retention"
 )
 
 const (
-	errNotTenant       = "managed resource is not a Tenant custom resource"
+	errNotTenant       = "managed resource is not a Tenant or ClusterTenant custom resource"
 	errTrackPCUsage    = "cannot track ProviderConfig usage"
 	errGetPC           = "cannot get ProviderConfig"
-	errGetCreds        = "cannot get credentials"
-	errNewClient       = "cannot create Grafana client"
 	errListTenants     = "cannot list Tenants"
+	errListCluster     = "cannot list ClusterTenants"
+	errListGroupSets   = "cannot list GroupSets"
 	errDuplicateTenant = "tenant with this tenantId already exists"
+	errEnsureOrg       = "cannot ensure Grafana organization"
+	errDeleteOrg       = "cannot delete Grafana organization"
+	errSyncAdmins      = "cannot sync Grafana organization admins"
+	errMoveOrg         = "cannot remove admins from previous Grafana organization"
+	errSyncRetention   = "cannot sync retention overrides"
+	errMappingRemoval  = "org_mapping entries of the tenant are not removed from Grafana yet"
 )
 
-// Setup adds a controller that reconciles Tenant managed resources.
+// Event reasons.
+const (
+	reasonMovedOrg       event.Reason = "MovedOrg"
+	reasonMappingRemoved event.Reason = "RemovedOrgMapping"
+	reasonForceDeleted   event.Reason = "ForceDeleted"
+)
+
+// tenantObject is a Tenant or a ClusterTenant. Both kinds share their
+// parameters and observation, and all Tenants and ClusterTenants of the same
+// Grafana instance share its org_mapping.
+type tenantObject interface {
+	resource.ModernManaged
+	GetParameters() *v1alpha1.TenantParameters
+	GetObservation() *v1alpha1.TenantObservation
+}
+
+// listTenants returns all Tenants and ClusterTenants.
+func listTenants(ctx context.Context, kube client.Reader) ([]tenantObject, error) {
+	list := &v1alpha1.TenantList{}
+	if err := kube.List(ctx, list); err != nil {
+		return nil, errors.Wrap(err, errListTenants)
+	}
+	clusterList := &v1alpha1.ClusterTenantList{}
+	if err := kube.List(ctx, clusterList); err != nil {
+		return nil, errors.Wrap(err, errListCluster)
+	}
+	tenants := make([]tenantObject, 0, len(list.Items)+len(clusterList.Items))
+	for i := range list.Items {
+		tenants = append(tenants, &list.Items[i])
+	}
+	for i := range clusterList.Items {
+		tenants = append(tenants, &clusterList.Items[i])
+	}
+	return tenants, nil
+}
+
+// Setup adds controllers that reconcile Tenant and ClusterTenant managed
+// resources. They share a Locker, since Tenants and ClusterTenants of the same
+// ProviderConfig write the same retention overrides. Their org_mapping is
+// written by the controllers added by SetupOrgMapping, whose syncs they watch
+// the configs for.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
+	locks := grafana.NewLocker()
+
 	name := managed.ControllerName(v1alpha1.TenantGroupKind)
+	r, err := newReconciler(mgr, o, name, locks, v1alpha1.TenantGroupVersionKind, &v1alpha1.TenantList{}, &apisv1alpha1.ProviderConfigUsage{})
+	if err != nil {
+		return err
+	}
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.Tenant{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&v1alpha1.GroupSet{}, handler.EnqueueRequestsFromMapFunc(referencingTenants(mgr.GetClient(), o.Logger)), builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&apisv1alpha1.ProviderConfig{}, handler.EnqueueRequestsFromMapFunc(configTenants(mgr.GetClient(), apisv1alpha1.ProviderConfigKind, false, o.Logger)), builder.WithPredicates(orgMappingSyncChanged())).
+		Watches(&apisv1alpha1.ClusterProviderConfig{}, handler.EnqueueRequestsFromMapFunc(configTenants(mgr.GetClient(), apisv1alpha1.ClusterProviderConfigKind, false, o.Logger)), builder.WithPredicates(orgMappingSyncChanged())).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter)); err != nil {
+		return err
+	}
+
+	name = managed.ControllerName(v1alpha1.ClusterTenantGroupKind)
+	r, err = newReconciler(mgr, o, name, locks, v1alpha1.ClusterTenantGroupVersionKind, &v1alpha1.ClusterTenantList{}, &apisv1alpha1.ClusterProviderConfigUsage{})
+	if err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ClusterTenant{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&apisv1alpha1.ClusterProviderConfig{}, handler.EnqueueRequestsFromMapFunc(configTenants(mgr.GetClient(), apisv1alpha1.ClusterProviderConfigKind, true, o.Logger)), builder.WithPredicates(orgMappingSyncChanged())).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// newReconciler returns a managed reconciler for Tenants or ClusterTenants,
+// tracking ProviderConfig usage with the supplied usage type.
+func newReconciler(mgr ctrl.Manager, o controller.Options, name string, locks *grafana.Locker, gvk schema.GroupVersionKind, list resource.ManagedList, usage resource.TypedProviderConfigUsage) (*managed.Reconciler, error) {
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 
 	opts := []managed.ReconcilerOption{
 		managed.WithExternalConnector(&connector{
-			kube:   mgr.GetClient(),
-			usage:  resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			logger: o.Logger,
+			kube:     mgr.GetClient(),
+			usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), usage),
+			locks:    locks,
+			recorder: recorder,
+			logger:   o.Logger,
 		}),
+		managed.WithReferenceResolver(&referenceResolver{kube: mgr.GetClient()}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 	}
 
 	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
@@ -80,36 +175,66 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	if o.MetricOptions != nil && o.MetricOptions.MRStateMetrics != nil {
 		stateMetricsRecorder := statemetrics.NewMRStateRecorder(
-			mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.TenantList{}, o.MetricOptions.PollStateMetricInterval,
+			mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, list, o.MetricOptions.PollStateMetricInterval,
 		)
 		if err := mgr.Add(stateMetricsRecorder); err != nil {
-			return errors.Wrap(err, "cannot register MR state metrics recorder for kind v1alpha1.TenantList")
+			return nil, errors.Wrapf(err, "cannot register MR state metrics recorder for kind v1alpha1.%sList", gvk.Kind)
 		}
 	}
 
-	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.TenantGroupVersionKind), opts...)
+	return managed.NewReconciler(mgr, resource.ManagedKind(gvk), opts...), nil
+}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Tenant{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+// referencingTenants returns a function that maps a GroupSet to the Tenants in
+// its namespace that reference it, so that changes to the GroupSet are synced
+// to them.
+func referencingTenants(kube client.Client, log logging.Logger) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		tenants, err := groupSetTenants(ctx, kube, obj)
+		if err != nil {
+			log.Info(errListTenants, "error", err)
+			return nil
+		}
+		reqs := make([]reconcile.Request, 0, len(tenants))
+		for _, t := range tenants {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(t)})
+		}
+		return reqs
+	}
+}
+
+// groupSetTenants returns the Tenants in the namespace of a GroupSet that
+// reference it.
+func groupSetTenants(ctx context.Context, kube client.Reader, gs client.Object) ([]*v1alpha1.Tenant, error) {
+	list := &v1alpha1.TenantList{}
+	if err := kube.List(ctx, list, client.InNamespace(gs.GetNamespace())); err != nil {
+		return nil, errors.Wrap(err, errListTenants)
+	}
+	var tenants []*v1alpha1.Tenant
+	for i := range list.Items {
+		t := &list.Items[i]
+		if slices.Contains(t.GetParameters().GroupSets, gs.GetName()) {
+			tenants = append(tenants, t)
+		}
+	}
+	return tenants, nil
 }
 
 // connector produces an ExternalClient by extracting Grafana credentials from
 // the referenced ProviderConfig.
 // the referenced ProviderConfig.
 type connector struct {
-	kube   client.Client
-	usage  *resource.ProviderConfigUsageTracker
-	logger logging.Logger
+	kube     client.Client
+	usage    *resource.ProviderConfigUsageTracker
+	locks    *grafana.Locker
+	recorder event.Recorder
+	logger   logging.Logger
 }
 
 // Connect extracts credentials from the ProviderConfig, creates a Grafana
 // client, and returns an external client.
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.Tenant)
+	cr, ok := mg.(tenantObject)
 	if !ok {
 		return nil, errors.New(errNotTenant)
 	}
@@ -118,73 +243,111 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	grafanaURL, creds, err := c.extractConfig(ctx, cr)
+	pc, err := c.extractConfig(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	gClient, err := clients.NewGrafanaClient(ctx, c.kube, pc.spec)
 	if err != nil {
 		return nil, err
 	}
 
-	gClient, err := grafana.NewClient(grafanaURL, creds)
+	groupSets, err := listGroupSets(ctx, c.kube)
 	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
+		return nil, err
 	}
 
 	return &external{
-		kube:   c.kube,
-		sso:    gClient.SsoSettings,
-		logger: c.logger,
+		kube:      c.kube,
+		orgs:      gClient.Orgs,
+		users:     gClient.Users,
+		accounts:  gClient.ServiceAccounts,
+		pc:        pc,
+		groupSets: groupSets,
+		locks:     c.locks,
+		recorder:  c.recorder,
+		logger:    c.logger,
 	}, nil
 }
 
-// extractConfig reads the ProviderConfig (namespaced or cluster-scoped) and
-// returns the Grafana URL and raw credential bytes.
-func (c *connector) extractConfig(ctx context.Context, cr *v1alpha1.Tenant) (string, []byte, error) {
-	ref := cr.Spec.ProviderConfigReference
-	if ref == nil {
-		return "", nil, errors.New(errGetPC + ": providerConfigRef is not set")
+// listGroupSets reads the groups of all GroupSets. Tenants of all namespaces
+// may share an org_mapping, so GroupSets of all namespaces are needed.
+func listGroupSets(ctx context.Context, kube client.Reader) (groupSetIndex, error) {
+	list := &v1alpha1.GroupSetList{}
+	if err := kube.List(ctx, list); err != nil {
+		return nil, errors.Wrap(err, errListGroupSets)
+	}
+	idx := make(groupSetIndex, len(list.Items))
+	for i := range list.Items {
+		gs := &list.Items[i]
+		idx[client.ObjectKeyFromObject(gs)] = gs.Spec.ForProvider
 	}
+	return idx, nil
+}
 
-	kind := ref.Kind
-	if kind == "" || kind == apisv1alpha1.ProviderConfigKind {
-		pc := &apisv1alpha1.ProviderConfig{}
-		if err := c.kube.Get(ctx, client.ObjectKey{
-			Namespace: cr.GetNamespace(),
-			Name:      ref.Name,
-		}, pc); err != nil {
-			return "", nil, errors.Wrap(err, errGetPC)
-		}
-		data, err := resource.CommonCredentialExtractor(ctx, pc.Spec.Credentials.Source, c.kube, pc.Spec.Credentials.CommonCredentialSelectors)
-		if err != nil {
-			return "", nil, errors.Wrap(err, errGetCreds)
-		}
-		return pc.Spec.GrafanaURL, data, nil
+// providerConfig is a resolved ProviderConfig or ClusterProviderConfig. Both
+// kinds share the same spec and status types.
+type providerConfig struct {
+	obj    client.Object
+	spec   *apisv1alpha1.ProviderConfigSpec
+	status *apisv1alpha1.ProviderConfigStatus
+}
+
+// extractConfig reads the ProviderConfig (namespaced or cluster-scoped)
+// referenced by a Tenant.
+func (c *connector) extractConfig(ctx context.Context, cr tenantObject) (*providerConfig, error) {
+	ref := cr.GetProviderConfigReference()
+	if ref == nil {
+		return nil, errors.New(errGetPC + ": providerConfigRef is not set")
 	}
 
-	if kind == apisv1alpha1.ClusterProviderConfigKind {
-		pc := &apisv1alpha1.ClusterProviderConfig{}
-		if err := c.kube.Get(ctx, client.ObjectKey{Name: ref.Name}, pc); err != nil {
-			return "", nil, errors.Wrap(err, errGetPC)
-		}
-		data, err := resource.CommonCredentialExtractor(ctx, pc.Spec.Credentials.Source, c.kube, pc.Spec.Credentials.CommonCredentialSelectors)
-		if err != nil {
-			return "", nil, errors.Wrap(err, errGetCreds)
-		}
-		return pc.Spec.GrafanaURL, data, nil
+	var obj resource.ProviderConfig
+	switch providerConfigKindOf(cr) {
+	case "", apisv1alpha1.ProviderConfigKind:
+		obj = &apisv1alpha1.ProviderConfig{}
+	case apisv1alpha1.ClusterProviderConfigKind:
+		obj = &apisv1alpha1.ClusterProviderConfig{}
+	default:
+		return nil, errors.New(errGetPC + ": unsupported provider config kind: " + ref.Kind)
+	}
+	key := providerConfigKeyOf(cr)
+	if err := c.kube.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: key.Name}, obj); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	return "", nil, errors.New(errGetPC + ": unsupported provider config kind: " + kind)
+	return newProviderConfig(obj), nil
+}
+
+// newProviderConfig returns the spec and status of a ProviderConfig or
+// ClusterProviderConfig.
+func newProviderConfig(obj resource.ProviderConfig) *providerConfig {
+	switch pc := obj.(type) {
+	case *apisv1alpha1.ProviderConfig:
+		return &providerConfig{obj: pc, spec: &pc.Spec, status: &pc.Status}
+	case *apisv1alpha1.ClusterProviderConfig:
+		return &providerConfig{obj: pc, spec: &pc.Spec, status: &pc.Status}
+	}
+	return &providerConfig{obj: obj, spec: &apisv1alpha1.ProviderConfigSpec{}, status: &apisv1alpha1.ProviderConfigStatus{}}
 }
 
 // external observes, creates, updates, and deletes Tenant resources,
-// syncing org_mapping to Grafana SSO settings on each mutation.
-// syncing org_mapping to Grafana SSO settings on each mutation.
+// syncing their organization, admins, retention and service accounts on each
+// mutation.
 type external struct {
-	kube   client.Client
-	sso    grafana.SSOClient
-	logger logging.Logger
+	kube      client.Client
+	orgs      grafana.OrgClient
+	users     grafana.UserClient
+	accounts  grafana.ServiceAccountClient
+	pc        *providerConfig
+	groupSets groupSetIndex
+	locks     *grafana.Locker
+	recorder  event.Recorder
+	logger    logging.Logger
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.Tenant)
+	cr, ok := mg.(tenantObject)
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotTenant)
 	}
@@ -195,12 +358,29 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	// For this "virtual" resource type where the CR is the source of truth,
-	// if the resource is being deleted, sync to Grafana (remove tenant from
-	// mapping) and then report ResourceExists: false so the managed reconciler
-	// can remove the finalizer. This replaces the normal Delete flow.
+	// if the resource is being deleted, clean up in Grafana and then report
+	// ResourceExists: false so the managed reconciler can remove the
+	// finalizer. The org_mapping controller drops the entries of the Tenant
+	// once it is deleting; until it confirms that, the Tenant is reported as
+	// existing so that Delete holds the finalizer.
 	if cr.GetDeletionTimestamp() != nil {
-		if err := c.syncGrafanaOrgMapping(ctx, cr, true); err != nil {
-			c.logger.Info("Failed to sync Grafana org mapping during delete", "error", err)
+		if err := c.deleteServiceAccounts(cr); err != nil {
+			c.logger.Info("Failed to delete Grafana service accounts", "error", err)
+		}
+		if err := c.deleteOrg(cr); err != nil {
+			c.logger.Info("Failed to delete Grafana organization", "error", err)
+		}
+		if err := c.syncRetentionOverrides(ctx, cr, true); err != nil {
+			c.logger.Info("Failed to sync retention overrides during delete", "error", err)
+		}
+		switch {
+		case forceDelete(cr):
+			c.recorder.Event(cr, event.Warning(reasonForceDeleted, errors.Errorf("removal of org_mapping entries was not confirmed because of the %s annotation", v1alpha1.AnnotationKeyForceDelete)))
+		case !orgMappingRemoved(cr, c.pc.status):
+			cr.SetConditions(v1alpha1.OrgMappingRemoving())
+			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+		default:
+			c.recorder.Event(cr, event.Normal(reasonMappingRemoved, "Removed the entries of the tenant from the Grafana org_mapping"))
 		}
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
@@ -208,7 +388,30 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	// Compare spec vs status to determine if an update is needed.
 	// The status is synced to spec during Create/Update and persisted by the
 	// managed reconciler.
-	upToDate := isUpToDate(cr)
+	upToDate := isUpToDate(cr, c.groupSets)
+
+	// Admins that could not be resolved may have signed in to Grafana since
+	// the last attempt, so keep retrying until all of them are provisioned.
+	if cr.GetCondition(v1alpha1.TypeAdminsProvisioned).Status == corev1.ConditionFalse {
+		upToDate = false
+	}
+
+	// The status is persisted even when a sync fails, so spec and status may
+	// match although Grafana never received them. Retry until it did.
+	if cr.GetCondition(v1alpha1.TypeGrafanaSynced).Status == corev1.ConditionFalse {
+		upToDate = false
+	}
+
+	// Grafana returns service account tokens only once, so the tokens
+	// published to the connection secret are read back from it. Missing
+	// tokens and tokens due for rotation are minted by Update.
+	tokens, err := c.publishedTokens(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if tokensDue(cr, tokens) {
+		upToDate = false
+	}
 
 	// For virtual resources, explicitly set the Available condition when the
 	// CR state is consistent (spec == status). This ensures the Ready status
@@ -216,27 +419,31 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if upToDate {
 		cr.SetConditions(xpv1.Available())
 
-		// Check for Grafana drift only when the CR is otherwise up-to-date.
-		// If drift is detected, trigger an Update to resync Grafana.
-		// Errors during drift check are logged but don't affect Ready state -
-		// this prevents infinite loops when Grafana is temporarily unreachable.
-		drifted, err := c.isGrafanaDrifted(cr)
+		// Check for a deleted organization only when the CR is otherwise
+		// up-to-date. Errors are logged but don't affect Ready state - this
+		// prevents infinite loops when Grafana is temporarily unreachable.
+		missing, err := c.isOrgMissing(cr)
 		if err != nil {
-			c.logger.Debug("Failed to check Grafana drift", "error", err)
-		} else if drifted {
-			c.logger.Info("Grafana org_mapping drift detected, triggering resync")
+			c.logger.Debug("Failed to check Grafana organization", "error", err)
+		} else if missing {
+			c.logger.Info("Grafana organization is missing, triggering resync")
 			upToDate = false
 		}
 	}
 
+	// The org_mapping controller of the ProviderConfig records which
+	// generation of each Tenant its last sync included.
+	cr.SetConditions(orgMappingCondition(cr, c.pc.status))
+
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: upToDate,
+		ResourceExists:    true,
+		ResourceUpToDate:  upToDate,
+		ConnectionDetails: c.connectionDetails(cr, tokens),
 	}, nil
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.Tenant)
+	cr, ok := mg.(tenantObject)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotTenant)
 	}
@@ -246,156 +453,460 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, err
 	}
 
-	meta.SetExternalName(cr, cr.Spec.ForProvider.TenantID)
-	syncStatus(cr)
+	meta.SetExternalName(cr, cr.GetParameters().TenantID)
+	if err := c.ensureOrg(cr); err != nil {
+		recordGrafanaSync(cr, err)
+		return managed.ExternalCreation{}, err
+	}
+	syncStatus(cr, c.groupSets)
 
-	// Grafana sync must succeed for Create - this ensures the tenant is
-	// properly registered in Grafana's org_mapping before the resource is Ready.
-	if err := c.syncGrafanaOrgMapping(ctx, cr, false); err != nil {
+	if err := c.syncAdmins(cr); err != nil {
+		recordGrafanaSync(cr, err)
+		return managed.ExternalCreation{}, err
+	}
+	if err := c.syncRetentionOverrides(ctx, cr, false); err != nil {
+		recordGrafanaSync(cr, err)
+		return managed.ExternalCreation{}, err
+	}
+	tokens, err := c.syncServiceAccounts(ctx, cr)
+	recordGrafanaSync(cr, err)
+	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
 
-	return managed.ExternalCreation{}, nil
+	return managed.ExternalCreation{ConnectionDetails: c.connectionDetails(cr, tokens)}, nil
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.Tenant)
+	cr, ok := mg.(tenantObject)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotTenant)
 	}
 
-	syncStatus(cr)
+	if err := c.ensureOrg(cr); err != nil {
+		recordGrafanaSync(cr, err)
+		return managed.ExternalUpdate{}, err
+	}
+	// The status keeps the previous orgId until the Tenant left that
+	// organization, so that a failed move is retried.
+	if err := c.moveOrg(cr); err != nil {
+		recordGrafanaSync(cr, err)
+		return managed.ExternalUpdate{}, err
+	}
+	syncStatus(cr, c.groupSets)
 
-	// Grafana sync is best-effort; log errors but don't block resource updates.
-	// The CR itself is the source of truth for this resource type.
-	if err := c.syncGrafanaOrgMapping(ctx, cr, false); err != nil {
-		c.logger.Info("Failed to sync Grafana org mapping", "error", err)
+	// A failure to sync one part does not stop the others. The first error
+	// is recorded in the GrafanaSynced condition and returned, so that the
+	// managed reconciler backs off and retries.
+	err := c.syncAdmins(cr)
+	if rerr := c.syncRetentionOverrides(ctx, cr, false); err == nil {
+		err = rerr
+	}
+	// Tokens of service accounts that failed to sync are still published,
+	// as long as the secret held them.
+	tokens, serr := c.syncServiceAccounts(ctx, cr)
+	if err == nil {
+		err = serr
+	}
+	recordGrafanaSync(cr, err)
+	if err == nil {
+		return managed.ExternalUpdate{ConnectionDetails: c.connectionDetails(cr, tokens)}, nil
 	}
 
-	return managed.ExternalUpdate{}, nil
+	// Grafana returns a token only when it is minted, and connection details
+	// are not published when Update fails. Publish new tokens first; Observe
+	// retries while GrafanaSynced is False, and that attempt fails.
+	if c.mintedTokens(ctx, cr, tokens) {
+		c.logger.Info("Failed to sync Tenant to Grafana, publishing new service account tokens before retrying", "error", err)
+		return managed.ExternalUpdate{ConnectionDetails: c.connectionDetails(cr, tokens)}, nil
+	}
+	return managed.ExternalUpdate{}, err
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
-	cr, ok := mg.(*v1alpha1.Tenant)
+	cr, ok := mg.(tenantObject)
 	if !ok {
 		return managed.ExternalDelete{}, errors.New(errNotTenant)
 	}
 
-	// Grafana sync is best-effort; log errors but don't block resource deletion.
+	// Sync is best-effort; log errors but don't block resource deletion.
 	// The CR itself is the source of truth for this resource type.
-	if err := c.syncGrafanaOrgMapping(ctx, cr, true); err != nil {
-		c.logger.Info("Failed to sync Grafana org mapping during delete", "error", err)
+	if err := c.syncRetentionOverrides(ctx, cr, true); err != nil {
+		c.logger.Info("Failed to sync retention overrides during delete", "error", err)
+	}
+
+	// Observe reports a deleting Tenant as existing until the org_mapping
+	// controller removed its entries. Failing makes the managed reconciler
+	// keep the finalizer, record a warning event and retry with backoff.
+	if !forceDelete(cr) && !orgMappingRemoved(cr, c.pc.status) {
+		if cond := c.pc.status.GetCondition(apisv1alpha1.TypeOrgMappingSynced); cond.Status == corev1.ConditionFalse {
+			return managed.ExternalDelete{}, errors.Errorf("%s: %s", errMappingRemoval, cond.Message)
+		}
+		return managed.ExternalDelete{}, errors.New(errMappingRemoval)
 	}
 
 	return managed.ExternalDelete{}, nil
 }
 
+// recordGrafanaSync records the outcome of an attempt to sync a Tenant to
+// Grafana in its status.
+func recordGrafanaSync(cr tenantObject, err error) {
+	cr.GetObservation().LastSyncAttempt = &metav1.Time{Time: time.Now().UTC().Truncate(time.Second)}
+	if err != nil {
+		cr.SetConditions(v1alpha1.GrafanaSyncFailed(err, cr.GetGeneration()))
+		return
+	}
+	cr.SetConditions(v1alpha1.GrafanaSynced(cr.GetGeneration()))
+}
+
+// forceDelete reports whether a deleting Tenant may go without confirming the
+// removal of its org_mapping entries.
+func forceDelete(cr tenantObject) bool {
+	return cr.GetAnnotations()[v1alpha1.AnnotationKeyForceDelete] == "true"
+}
+
+// orgMappingRemoved reports whether the last successful sync of the
+// org_mapping of a config excluded a Tenant, so that Grafana no longer holds
+// its entries.
+func orgMappingRemoved(cr tenantObject, status *apisv1alpha1.ProviderConfigStatus) bool {
+	s := status.OrgMappingSync
+	if s == nil {
+		return false
+	}
+	_, included := s.Tenants[string(cr.GetUID())]
+	return !included
+}
+
 func (c *external) Disconnect(ctx context.Context) error {
 	return nil
 }
 
-// syncGrafanaOrgMapping lists all Tenants, builds org_mapping, and writes it to
-// Grafana SSO settings. If deleting is true, the current tenant is excluded.
-func (c *external) syncGrafanaOrgMapping(ctx context.Context, cr *v1alpha1.Tenant, deleting bool) error {
-	list := &v1alpha1.TenantList{}
-	if err := c.kube.List(ctx, list); err != nil {
-		return errors.Wrap(err, errListTenants)
+// sharedTenants returns the Tenants that share the ProviderConfig of the given
+// Tenant and are not being deleted, including the Tenant itself unless
+// deleting is true.
+func (c *external) sharedTenants(ctx context.Context, cr tenantObject, deleting bool) ([]tenantObject, error) {
+	all, err := listTenants(ctx, c.kube)
+	if err != nil {
+		return nil, err
 	}
 
-	mappings := make([]grafana.TenantMapping, 0, len(list.Items))
-	for i := range list.Items {
-		t := &list.Items[i]
-		// Skip the tenant being deleted.
-		if deleting && t.GetUID() == cr.GetUID() {
+	target := providerConfigKeyOf(cr)
+	tenants := make([]tenantObject, 0, len(all))
+	for _, t := range all {
+		// Skip tenants whose own deletion drops them from the overrides.
+		if t.GetUID() != cr.GetUID() && t.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if t.GetUID() == cr.GetUID() {
+			// Skip the tenant being deleted.
+			if deleting {
+				continue
+			}
+			// Status changes made during this reconcile (e.g. a newly created
+			// organization) are not persisted yet.
+			t = cr
+		}
+		// Skip tenants that target a different Grafana instance.
+		if providerConfigKeyOf(t) != target {
 			continue
 		}
-		mappings = append(mappings, grafana.TenantMapping{
-			OrgID:        t.Spec.ForProvider.OrgID,
-			ViewerGroups: t.Spec.ForProvider.ViewerGroups,
-			EditorGroups: t.Spec.ForProvider.EditorGroups,
-			AdminGroups:  t.Spec.ForProvider.AdminGroups,
+		tenants = append(tenants, t)
+	}
+	return tenants, nil
+}
+
+// syncRetentionOverrides renders the retention policies of all Tenants that
+// share the ProviderConfig of the given Tenant to the ConfigMap or Secret
+// named in its retentionOverrides. If deleting is true, the current tenant is
+// excluded. It does nothing when the ProviderConfig names no such object.
+func (c *external) syncRetentionOverrides(ctx context.Context, cr tenantObject, deleting bool) error {
+	if c.pc.spec.RetentionOverrides == nil {
+		return nil
+	}
+	defer c.locks.Lock("retention/" + providerConfigKeyOf(cr).String())()
+
+	tenants, err := c.sharedTenants(ctx, cr, deleting)
+	if err != nil {
+		return err
+	}
+
+	policies := make([]retention.TenantRetention, 0, len(tenants))
+	for _, t := range tenants {
+		r := t.GetParameters().Retention
+		policies = append(policies, retention.TenantRetention{
+			TenantID: t.GetParameters().TenantID,
+			Logs:     r.Logs,
+			Metrics:  r.Metrics,
+			Traces:   r.Traces,
+			Profiles: r.Profiles,
 		})
 	}
+	files, err := retention.Render(policies)
+	if err != nil {
+		return errors.Wrap(err, errSyncRetention)
+	}
+
+	ro := c.pc.spec.RetentionOverrides
+	key := client.ObjectKey{Namespace: ro.Namespace, Name: ro.Name}
+	if key.Namespace == "" {
+		key.Namespace = c.pc.obj.GetNamespace()
+	}
+	if key.Namespace == "" {
+		return errors.New(errSyncRetention + ": retentionOverrides.namespace is required for a ClusterProviderConfig")
+	}
+
+	var (
+		obj    client.Object
+		mutate controllerutil.MutateFn
+	)
+	if ro.Kind == apisv1alpha1.RetentionOverridesKindSecret {
+		s := &corev1.Secret{}
+		mutate = func() error {
+			s.Data = make(map[string][]byte, len(files))
+			for k, v := range files {
+				s.Data[k] = []byte(v)
+			}
+			return nil
+		}
+		obj = s
+	} else {
+		cm := &corev1.ConfigMap{}
+		mutate = func() error {
+			cm.Data = files
+			return nil
+		}
+		obj = cm
+	}
+	obj.SetNamespace(key.Namespace)
+	obj.SetName(key.Name)
 
-	orgMapping := grafana.BuildOrgMapping(mappings)
-	c.logger.Debug("Syncing Grafana org mapping", "org_mapping", orgMapping)
+	// CreateOrUpdate only writes when the rendered overrides differ from
+	// what the object holds.
+	_, err = controllerutil.CreateOrUpdate(ctx, c.kube, obj, mutate)
+	return errors.Wrap(err, errSyncRetention)
+}
 
-	if err := grafana.SyncOrgMapping(ctx, c.sso, mappings); err != nil {
-		return errors.Wrap(err, "cannot sync Grafana org mapping")
+// providerConfigKey identifies the ProviderConfig (and therefore the Grafana
+// instance) a Tenant resolves to.
+type providerConfigKey struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (k providerConfigKey) String() string {
+	return k.Kind + "/" + k.Namespace + "/" + k.Name
+}
+
+// providerConfigKindOf returns the kind of ProviderConfig a Tenant
+// references. ClusterTenants always use a ClusterProviderConfig.
+func providerConfigKindOf(cr tenantObject) string {
+	if _, ok := cr.(*v1alpha1.ClusterTenant); ok {
+		return apisv1alpha1.ClusterProviderConfigKind
 	}
-	return nil
+	if ref := cr.GetProviderConfigReference(); ref != nil {
+		return ref.Kind
+	}
+	return ""
 }
 
-// validateUniqueTenantID checks that no other Tenant in the cluster has the same tenantId.
-func (c *external) validateUniqueTenantID(ctx context.Context, cr *v1alpha1.Tenant) error {
-	list := &v1alpha1.TenantList{}
-	if err := c.kube.List(ctx, list); err != nil {
-		return errors.Wrap(err, errListTenants)
+// providerConfigKeyOf resolves the ProviderConfig reference of a Tenant the
+// same way extractConfig does: an empty kind means a namespaced ProviderConfig
+// in the Tenant's namespace, while a ClusterProviderConfig has no namespace.
+func providerConfigKeyOf(cr tenantObject) providerConfigKey {
+	ref := cr.GetProviderConfigReference()
+	if ref == nil {
+		return providerConfigKey{}
+	}
+	if providerConfigKindOf(cr) == apisv1alpha1.ClusterProviderConfigKind {
+		return providerConfigKey{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: ref.Name}
 	}
+	return providerConfigKey{
+		Kind:      apisv1alpha1.ProviderConfigKind,
+		Namespace: cr.GetNamespace(),
+		Name:      ref.Name,
+	}
+}
 
-	for i := range list.Items {
-		t := &list.Items[i]
+// validateUniqueTenantID checks that no other Tenant or ClusterTenant in the
+// cluster has the same tenantId.
+func (c *external) validateUniqueTenantID(ctx context.Context, cr tenantObject) error {
+	tenants, err := listTenants(ctx, c.kube)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tenants {
 		// Skip self (in case of updates, though this is called from Create)
 		if t.GetUID() == cr.GetUID() {
 			continue
 		}
-		if t.Spec.ForProvider.TenantID == cr.Spec.ForProvider.TenantID {
-			return errors.Errorf("%s: %s", errDuplicateTenant, cr.Spec.ForProvider.TenantID)
+		if t.GetParameters().TenantID == cr.GetParameters().TenantID {
+			return errors.Errorf("%s: %s", errDuplicateTenant, cr.GetParameters().TenantID)
 		}
 	}
 	return nil
 }
 
-// isGrafanaDrifted checks whether this tenant's org_mapping entries are present in
-// the Grafana SSO settings. Returns true if the tenant is missing from the mapping.
-func (c *external) isGrafanaDrifted(cr *v1alpha1.Tenant) (bool, error) {
-	// If the tenant has no groups, there's nothing to check in Grafana.
-	// No entries will be generated, so we consider it "not drifted".
-	if len(cr.Spec.ForProvider.ViewerGroups) == 0 && len(cr.Spec.ForProvider.EditorGroups) == 0 && len(cr.Spec.ForProvider.AdminGroups) == 0 {
-		return false, nil
+// ensureOrg creates or renames the Grafana organization of a Tenant that
+// sets createOrg, recording its ID in status.
+func (c *external) ensureOrg(cr tenantObject) error {
+	if !cr.GetParameters().CreateOrg {
+		return nil
 	}
+	id, err := grafana.EnsureOrg(c.orgs, cr.GetObservation().OrgID, cr.GetParameters().OrgName)
+	if err != nil {
+		return errors.Wrap(err, errEnsureOrg)
+	}
+	cr.GetObservation().OrgID = id
+	return nil
+}
 
-	resp, err := c.sso.GetProviderSettings("generic_oauth")
+// syncAdmins grants the Tenant's admins the Admin role in its Grafana
+// organization and removes the admins recorded in status that were dropped
+// since. The status records the new admins only once this succeeded, so that
+// a failed removal is retried. Admins that cannot be resolved to a Grafana
+// user are reported in the AdminsProvisioned condition.
+func (c *external) syncAdmins(cr tenantObject) error {
+	admins := cr.GetParameters().Admins
+	previous := cr.GetObservation().Admins
+	if len(admins) == 0 && len(previous) == 0 {
+		return nil
+	}
+	unresolved, err := grafana.SyncOrgAdmins(c.users, c.orgs, orgIDOf(cr), admins, previous)
 	if err != nil {
-		if grafana.IsNotFound(err) {
-			// SSO not configured yet - this is drift (needs to be set up)
-			return true, nil
+		return errors.Wrap(err, errSyncAdmins)
+	}
+	cr.GetObservation().Admins = admins
+	if len(unresolved) > 0 {
+		cr.SetConditions(v1alpha1.AdminsUnresolved(unresolved))
+		return nil
+	}
+	cr.SetConditions(v1alpha1.AdminsProvisioned())
+	return nil
+}
+
+// moveOrg moves a Tenant whose orgId changed away from the Grafana
+// organization recorded in its status: its admins are removed from that
+// organization, and an event records the move. It does nothing for a Tenant
+// that did not move.
+func (c *external) moveOrg(cr tenantObject) error {
+	from := cr.GetObservation().OrgID
+	if cr.GetParameters().CreateOrg || from == "" || from == cr.GetParameters().OrgID {
+		return nil
+	}
+	if admins := cr.GetObservation().Admins; len(admins) > 0 {
+		if _, err := grafana.SyncOrgAdmins(c.users, c.orgs, from, nil, admins); err != nil {
+			return errors.Wrapf(err, "%s %s", errMoveOrg, from)
 		}
-		return false, err
 	}
+	c.recorder.Event(cr, event.Normal(reasonMovedOrg, fmt.Sprintf("Moved tenant from Grafana organization %s to %s", from, cr.GetParameters().OrgID)))
+	return nil
+}
 
-	settings, ok := resp.Payload.Settings.(map[string]any)
-	if !ok {
-		return true, nil
+// isOrgMissing reports whether the Grafana organization of a Tenant that
+// sets createOrg no longer exists.
+func (c *external) isOrgMissing(cr tenantObject) (bool, error) {
+	if !cr.GetParameters().CreateOrg {
+		return false, nil
 	}
+	exists, err := grafana.OrgExists(c.orgs, cr.GetObservation().OrgID)
+	return !exists, err
+}
 
-	orgMapping, _ := settings["orgMapping"].(string)
-	return !grafana.OrgMappingContains(orgMapping, cr.Spec.ForProvider.OrgID), nil
+// deleteOrg deletes the Grafana organization of a Tenant that sets createOrg
+// and an orgDeletionPolicy of Delete.
+func (c *external) deleteOrg(cr tenantObject) error {
+	p := cr.GetParameters()
+	if !p.CreateOrg || p.OrgDeletionPolicy != xpv1.DeletionDelete || cr.GetObservation().OrgID == "" {
+		return nil
+	}
+	return errors.Wrap(grafana.DeleteOrg(c.orgs, cr.GetObservation().OrgID), errDeleteOrg)
 }
 
-// syncStatus copies spec fields into status and sets the lastUpdated timestamp.
-func syncStatus(cr *v1alpha1.Tenant) {
-	cr.Status.AtProvider = v1alpha1.TenantObservation{
-		TenantID:     cr.Spec.ForProvider.TenantID,
-		OrgID:        cr.Spec.ForProvider.OrgID,
-		Admins:       cr.Spec.ForProvider.Admins,
-		ViewerGroups: cr.Spec.ForProvider.ViewerGroups,
-		EditorGroups: cr.Spec.ForProvider.EditorGroups,
-		AdminGroups:  cr.Spec.ForProvider.AdminGroups,
-		Retention:    cr.Spec.ForProvider.Retention,
+// orgIDOf returns the Grafana organization ID a Tenant maps to: the spec
+// value, or the ID recorded in status for organizations created by the
+// provider.
+func orgIDOf(cr tenantObject) string {
+	if cr.GetParameters().CreateOrg {
+		return cr.GetObservation().OrgID
+	}
+	return cr.GetParameters().OrgID
+}
+
+// groupSetIndex holds the groups of GroupSets by namespace and name.
+type groupSetIndex map[types.NamespacedName]v1alpha1.GroupSetParameters
+
+// effectiveGroups returns the groups per role of a Tenant: its own groups and
+// those of the GroupSets it references, normalized by grafana.NormalizeGroups.
+// GroupSets that do not exist contribute no groups.
+func effectiveGroups(cr tenantObject, groupSets groupSetIndex) v1alpha1.GroupSetParameters {
+	p := cr.GetParameters()
+	groups := v1alpha1.GroupSetParameters{
+		ViewerGroups: slices.Clone(p.ViewerGroups),
+		EditorGroups: slices.Clone(p.EditorGroups),
+		AdminGroups:  slices.Clone(p.AdminGroups),
+	}
+	for _, name := range p.GroupSets {
+		gs := groupSets[types.NamespacedName{Namespace: cr.GetNamespace(), Name: name}]
+		groups.ViewerGroups = append(groups.ViewerGroups, gs.ViewerGroups...)
+		groups.EditorGroups = append(groups.EditorGroups, gs.EditorGroups...)
+		groups.AdminGroups = append(groups.AdminGroups, gs.AdminGroups...)
+	}
+	groups.ViewerGroups = grafana.NormalizeGroups(groups.ViewerGroups)
+	groups.EditorGroups = grafana.NormalizeGroups(groups.EditorGroups)
+	groups.AdminGroups = grafana.NormalizeGroups(groups.AdminGroups)
+	return groups
+}
+
+// tenantMapping returns the org_mapping input for a Tenant.
+func tenantMapping(cr tenantObject, groupSets groupSetIndex) grafana.TenantMapping {
+	groups := effectiveGroups(cr, groupSets)
+	return grafana.TenantMapping{
+		TenantID:     cr.GetParameters().TenantID,
+		OrgID:        orgIDOf(cr),
+		ViewerGroups: groups.ViewerGroups,
+		EditorGroups: groups.EditorGroups,
+		AdminGroups:  groups.AdminGroups,
+	}
+}
+
+// syncStatus copies spec fields into status and sets the lastUpdated
+// timestamp. The groups recorded are the Tenant's effective groups. Admins
+// are recorded by syncAdmins once they are provisioned.
+func syncStatus(cr tenantObject, groupSets groupSetIndex) {
+	groups := effectiveGroups(cr, groupSets)
+	*cr.GetObservation() = v1alpha1.TenantObservation{
+		TenantID:     cr.GetParameters().TenantID,
+		OrgID:        orgIDOf(cr),
+		OrgName:      cr.GetParameters().OrgName,
+		Admins:       cr.GetObservation().Admins,
+		ViewerGroups: groups.ViewerGroups,
+		EditorGroups: groups.EditorGroups,
+		AdminGroups:  groups.AdminGroups,
+		Retention:    cr.GetParameters().Retention,
 		LastUpdated:  time.Now().UTC().Format(time.RFC3339),
+
+		ConnectionServiceAccount: cr.GetObservation().ConnectionServiceAccount,
+		ServiceAccounts:          cr.GetObservation().ServiceAccounts,
 	}
 }
 
-// isUpToDate compares spec.forProvider against status.atProvider.
-func isUpToDate(cr *v1alpha1.Tenant) bool {
-	spec := cr.Spec.ForProvider
-	obs := cr.Status.AtProvider
+// isUpToDate compares spec.forProvider, with the groups of referenced
+// GroupSets, against status.atProvider.
+func isUpToDate(cr tenantObject, groupSets groupSetIndex) bool {
+	spec := cr.GetParameters()
+	obs := cr.GetObservation()
+	groups := effectiveGroups(cr, groupSets)
 
 	if spec.TenantID != obs.TenantID {
 		return false
 	}
-	if spec.OrgID != obs.OrgID {
+	if spec.CreateOrg {
+		if obs.OrgID == "" || spec.OrgName != obs.OrgName {
+			return false
+		}
+	} else if spec.OrgID != obs.OrgID {
 		return false
 	}
 	if spec.Retention != obs.Retention {
@@ -404,30 +915,21 @@ func isUpToDate(cr *v1alpha1.Tenant) bool {
 	if !slicesEqual(spec.Admins, obs.Admins) {
 		return false
 	}
-	if !slicesEqual(spec.ViewerGroups, obs.ViewerGroups) {
+	if !slicesEqual(groups.ViewerGroups, obs.ViewerGroups) {
 		return false
 	}
-	if !slicesEqual(spec.EditorGroups, obs.EditorGroups) {
+	if !slicesEqual(groups.EditorGroups, obs.EditorGroups) {
 		return false
 	}
-	if !slicesEqual(spec.AdminGroups, obs.AdminGroups) {
+	if !slicesEqual(groups.AdminGroups, obs.AdminGroups) {
 		return false
 	}
-	return true
+	return serviceAccountsUpToDate(cr)
 }
 
-// slicesEqual compares two string slices, treating nil and empty as equivalent.
+// slicesEqual compares two string slices after normalizing them with
+// grafana.NormalizeGroups, so order, whitespace and duplicates are ignored
+// and nil and empty are equivalent.
 func slicesEqual(a, b []string) bool {
-	if len(a) == 0 && len(b) == 0 {
-		return true
-	}
-	if len(a) != len(b) {
-		return false
-	}
-	for i := range a {
-		if a[i] != b[i] {
-			return false
-		}
-	}
-	return true
+	return slices.Equal(grafana.NormalizeGroups(a), grafana.NormalizeGroups(b))
 }
@@ -18,14 +18,23 @@ package tenant
 
 import (
 	"context"
+	"maps"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	clfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
@@ -33,26 +42,41 @@ import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
 
+	"github.com/go-openapi/runtime"
+	"github.com/grafana/grafana-openapi-client-go/client/orgs"
 	"github.com/grafana/grafana-openapi-client-go/client/sso_settings"
+	"github.com/grafana/grafana-openapi-client-go/client/users"
 	"github.com/grafana/grafana-openapi-client-go/models"
 
 	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+	apisv1alpha1 "github.com/loafoe/provider-orgmapper/apis/v1alpha1"
 	"github.com/loafoe/provider-orgmapper/internal/grafana"
+	"github.com/loafoe/provider-orgmapper/internal/retention"
 )
 
 // mockSSO implements grafana.SSOClient for controller tests.
 type mockSSO struct {
 	getResp *sso_settings.GetProviderSettingsOK
 	getErr  error
+	putKeys []string
 	putBody *models.UpdateProviderSettingsParamsBody
 	putErr  error
 }
 
 func (m *mockSSO) GetProviderSettings(key string, _ ...sso_settings.ClientOption) (*sso_settings.GetProviderSettingsOK, error) {
-	return m.getResp, m.getErr
+	if m.getResp == nil || m.getErr != nil {
+		return m.getResp, m.getErr
+	}
+	// Like Grafana, return fresh settings on every call.
+	settings, ok := m.getResp.Payload.Settings.(map[string]any)
+	if !ok {
+		return m.getResp, nil
+	}
+	return &sso_settings.GetProviderSettingsOK{Payload: &models.GetProviderSettingsOKBody{Settings: maps.Clone(settings)}}, nil
 }
 
 func (m *mockSSO) UpdateProviderSettings(key string, body *models.UpdateProviderSettingsParamsBody, _ ...sso_settings.ClientOption) (*sso_settings.UpdateProviderSettingsNoContent, error) {
+	m.putKeys = append(m.putKeys, key)
 	m.putBody = body
 	if m.putErr != nil {
 		return nil, m.putErr
@@ -60,6 +84,95 @@ func (m *mockSSO) UpdateProviderSettings(key string, body *models.UpdateProvider
 	return &sso_settings.UpdateProviderSettingsNoContent{}, nil
 }
 
+// mockOrgs implements grafana.OrgClient for controller tests, backed by a map
+// of org ID to name and a map of user ID to role for members of any org.
+type mockOrgs struct {
+	orgs     map[int64]string
+	members  map[int64]string
+	deleted  []int64
+	usersErr error
+}
+
+// defaultMockOrgs returns a mock holding the orgs named org-1 and org-2.
+func defaultMockOrgs() *mockOrgs {
+	return &mockOrgs{orgs: map[int64]string{1: "org-1", 2: "org-2"}, members: map[int64]string{}}
+}
+
+// mockUsers implements grafana.UserClient for controller tests. Logins are
+// resolved to their index in the list, plus one.
+type mockUsers struct {
+	logins []string
+}
+
+func (m *mockUsers) GetUserByLoginOrEmail(loginOrEmail string, _ ...users.ClientOption) (*users.GetUserByLoginOrEmailOK, error) {
+	for i, l := range m.logins {
+		if l == loginOrEmail {
+			return &users.GetUserByLoginOrEmailOK{Payload: &models.UserProfileDTO{ID: int64(i + 1), Login: l}}, nil
+		}
+	}
+	return nil, &users.GetUserByLoginOrEmailNotFound{}
+}
+
+func (m *mockOrgs) GetOrgUsers(orgID int64, _ ...orgs.ClientOption) (*orgs.GetOrgUsersOK, error) {
+	if m.usersErr != nil {
+		return nil, m.usersErr
+	}
+	var payload []*models.OrgUserDTO
+	for id, role := range m.members {
+		payload = append(payload, &models.OrgUserDTO{OrgID: orgID, UserID: id, Role: role})
+	}
+	return &orgs.GetOrgUsersOK{Payload: payload}, nil
+}
+
+func (m *mockOrgs) AddOrgUser(_ int64, body *models.AddOrgUserCommand, _ ...orgs.ClientOption) (*orgs.AddOrgUserOK, error) {
+	m.members[int64(len(m.members)+1)] = body.Role
+	return &orgs.AddOrgUserOK{}, nil
+}
+
+func (m *mockOrgs) UpdateOrgUser(params *orgs.UpdateOrgUserParams, _ ...orgs.ClientOption) (*orgs.UpdateOrgUserOK, error) {
+	m.members[params.UserID] = params.Body.Role
+	return &orgs.UpdateOrgUserOK{}, nil
+}
+
+func (m *mockOrgs) RemoveOrgUser(userID int64, _ int64, _ ...orgs.ClientOption) (*orgs.RemoveOrgUserOK, error) {
+	delete(m.members, userID)
+	return &orgs.RemoveOrgUserOK{}, nil
+}
+
+func (m *mockOrgs) CreateOrg(body *models.CreateOrgCommand, _ ...orgs.ClientOption) (*orgs.CreateOrgOK, error) {
+	id := int64(len(m.orgs) + 1)
+	m.orgs[id] = body.Name
+	return &orgs.CreateOrgOK{Payload: &models.CreateOrgOKBody{OrgID: &id}}, nil
+}
+
+func (m *mockOrgs) GetOrgByID(id int64, _ ...orgs.ClientOption) (*orgs.GetOrgByIDOK, error) {
+	name, ok := m.orgs[id]
+	if !ok {
+		return nil, runtime.NewAPIError("not found", nil, http.StatusNotFound)
+	}
+	return &orgs.GetOrgByIDOK{Payload: &models.OrgDetailsDTO{ID: id, Name: name}}, nil
+}
+
+func (m *mockOrgs) GetOrgByName(name string, _ ...orgs.ClientOption) (*orgs.GetOrgByNameOK, error) {
+	for id, n := range m.orgs {
+		if n == name {
+			return &orgs.GetOrgByNameOK{Payload: &models.OrgDetailsDTO{ID: id, Name: n}}, nil
+		}
+	}
+	return nil, runtime.NewAPIError("not found", nil, http.StatusNotFound)
+}
+
+func (m *mockOrgs) UpdateOrg(id int64, body *models.UpdateOrgForm, _ ...orgs.ClientOption) (*orgs.UpdateOrgOK, error) {
+	m.orgs[id] = body.Name
+	return &orgs.UpdateOrgOK{}, nil
+}
+
+func (m *mockOrgs) DeleteOrgByID(id int64, _ ...orgs.ClientOption) (*orgs.DeleteOrgByIDOK, error) {
+	delete(m.orgs, id)
+	m.deleted = append(m.deleted, id)
+	return &orgs.DeleteOrgByIDOK{}, nil
+}
+
 // defaultMockSSO returns a mock that reports the expected orgMapping for the
 // given orgIDs. Each org gets a default viewer group entry.
 func defaultMockSSO(orgIDs ...string) *mockSSO {
@@ -93,12 +206,27 @@ func tenantWithSpec(tenantID, orgID string, admins []string, retention v1alpha1.
 	return t
 }
 
+const testGrafanaURL = "https://grafana.example.com"
+
+// testProviderConfig returns the ProviderConfig Tenants in tests use. Its
+// last org_mapping sync included no Tenants.
+func testProviderConfig() *providerConfig {
+	pc := &apisv1alpha1.ProviderConfig{}
+	pc.SetName("default")
+	pc.Spec.GrafanaURL = testGrafanaURL
+	pc.Status.OrgMappingSync = &apisv1alpha1.OrgMappingSync{}
+	return newProviderConfig(pc)
+}
+
 func newFakeKube(objs ...client.Object) client.Client {
-	scheme := runtime.NewScheme()
+	scheme := kruntime.NewScheme()
 	_ = v1alpha1.SchemeBuilder.AddToScheme(scheme)
+	_ = apisv1alpha1.SchemeBuilder.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
 	return clfake.NewClientBuilder().
 		WithScheme(scheme).
 		WithObjects(objs...).
+		WithStatusSubresource(&apisv1alpha1.ProviderConfig{}, &apisv1alpha1.ClusterProviderConfig{}, &apisv1alpha1.OrgMapping{}, &apisv1alpha1.ClusterOrgMapping{}).
 		Build()
 }
 
@@ -120,13 +248,11 @@ func TestObserve(t *testing.T) {
 
 	cases := map[string]struct {
 		reason string
-		sso    *mockSSO
 		args   args
 		want   want
 	}{
 		"NoExternalName": {
 			reason: "Should return ResourceExists false when no external name is set.",
-			sso:    defaultMockSSO(),
 			args: args{
 				ctx: context.Background(),
 				mg:  tenantWithSpec("acme", "org-1", nil, retention),
@@ -137,7 +263,6 @@ func TestObserve(t *testing.T) {
 		},
 		"ExternalNameSetButNoStatus": {
 			reason: "Should return ResourceExists false when external name is set but status is empty.",
-			sso:    defaultMockSSO(),
 			args: args{
 				ctx: context.Background(),
 				mg: func() resource.Managed {
@@ -147,41 +272,46 @@ func TestObserve(t *testing.T) {
 				}(),
 			},
 			want: want{
-				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{keyTenantID: []byte("acme"), keyOrgID: []byte("org-1"), keyGrafanaURL: []byte(testGrafanaURL)},
+				},
 			},
 		},
 		"UpToDate": {
 			reason: "Should return ResourceExists true and ResourceUpToDate true when spec matches status.",
-			sso:    defaultMockSSO("org-1"),
 			args: args{
 				ctx: context.Background(),
 				mg: func() resource.Managed {
 					cr := tenantWithSpec("acme", "org-1", []string{"admin1"}, retention)
+					cr.Spec.ForProvider.ViewerGroups = []string{"default-viewers"}
 					meta.SetExternalName(cr, "acme")
 					cr.Status.AtProvider = v1alpha1.TenantObservation{
-						TenantID:    "acme",
-						OrgID:       "org-1",
-						Admins:      []string{"admin1"},
-						Retention:   retention,
-						LastUpdated: "2025-01-01T00:00:00Z",
+						TenantID:     "acme",
+						OrgID:        "org-1",
+						Admins:       []string{"admin1"},
+						ViewerGroups: []string{"default-viewers"},
+						Retention:    retention,
+						LastUpdated:  "2025-01-01T00:00:00Z",
 					}
 					return cr
 				}(),
 			},
 			want: want{
 				o: managed.ExternalObservation{
-					ResourceExists:   true,
-					ResourceUpToDate: true,
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{keyTenantID: []byte("acme"), keyOrgID: []byte("org-1"), keyGrafanaURL: []byte(testGrafanaURL)},
 				},
 			},
 		},
-		"NotUpToDate": {
-			reason: "Should return ResourceUpToDate false when spec diverges from status.",
-			sso:    defaultMockSSO("org-1"),
+		"GrafanaSyncFailed": {
+			reason: "Should return ResourceUpToDate false when spec matches status but the last sync to Grafana failed.",
 			args: args{
 				ctx: context.Background(),
 				mg: func() resource.Managed {
-					cr := tenantWithSpec("acme", "org-2", nil, retention)
+					cr := tenantWithSpec("acme", "org-1", nil, retention)
 					meta.SetExternalName(cr, "acme")
 					cr.Status.AtProvider = v1alpha1.TenantObservation{
 						TenantID:    "acme",
@@ -189,45 +319,44 @@ func TestObserve(t *testing.T) {
 						Retention:   retention,
 						LastUpdated: "2025-01-01T00:00:00Z",
 					}
+					cr.SetConditions(v1alpha1.GrafanaSyncFailed(errors.New("boom"), 1))
 					return cr
 				}(),
 			},
 			want: want{
 				o: managed.ExternalObservation{
-					ResourceExists:   true,
-					ResourceUpToDate: false,
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{keyTenantID: []byte("acme"), keyOrgID: []byte("org-1"), keyGrafanaURL: []byte(testGrafanaURL)},
 				},
 			},
 		},
-		"GrafanaDriftDetected": {
-			reason: "Should return ResourceUpToDate false when Grafana org_mapping drifted, to trigger resync.",
-			sso:    defaultMockSSO("org-OTHER"),
+		"NotUpToDate": {
+			reason: "Should return ResourceUpToDate false when spec diverges from status.",
 			args: args{
 				ctx: context.Background(),
 				mg: func() resource.Managed {
-					cr := tenantWithSpec("acme", "org-1", nil, retention)
-					cr.Spec.ForProvider.ViewerGroups = []string{"team-a"} // Has groups, so drift check runs
+					cr := tenantWithSpec("acme", "org-2", nil, retention)
 					meta.SetExternalName(cr, "acme")
 					cr.Status.AtProvider = v1alpha1.TenantObservation{
-						TenantID:     "acme",
-						OrgID:        "org-1",
-						ViewerGroups: []string{"team-a"},
-						Retention:    retention,
-						LastUpdated:  "2025-01-01T00:00:00Z",
+						TenantID:    "acme",
+						OrgID:       "org-1",
+						Retention:   retention,
+						LastUpdated: "2025-01-01T00:00:00Z",
 					}
 					return cr
 				}(),
 			},
 			want: want{
 				o: managed.ExternalObservation{
-					ResourceExists:   true,
-					ResourceUpToDate: false,
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{keyTenantID: []byte("acme"), keyOrgID: []byte("org-2"), keyGrafanaURL: []byte(testGrafanaURL)},
 				},
 			},
 		},
 		"NotATenant": {
 			reason: "Should return an error if the managed resource is not a Tenant.",
-			sso:    defaultMockSSO(),
 			args: args{
 				ctx: context.Background(),
 				mg:  &fake.Managed{},
@@ -240,7 +369,7 @@ func TestObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{sso: tc.sso, logger: logging.NewNopLogger()}
+			e := external{pc: testProviderConfig(), locks: grafana.NewLocker(), logger: logging.NewNopLogger()}
 			got, err := e.Observe(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -268,26 +397,23 @@ func TestCreate(t *testing.T) {
 	cases := map[string]struct {
 		reason string
 		kube   client.Client
-		sso    *mockSSO
 		args   args
 		want   want
 	}{
 		"Success": {
-			reason: "Should set external name, populate status, and sync Grafana.",
+			reason: "Should set external name and populate status.",
 			kube:   newFakeKube(),
-			sso:    defaultMockSSO(),
 			args: args{
 				ctx: context.Background(),
 				mg:  tenantWithSpec("acme", "org-1", []string{"admin1"}, retention),
 			},
 			want: want{
-				o: managed.ExternalCreation{},
+				o: managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{keyTenantID: []byte("acme"), keyOrgID: []byte("org-1"), keyGrafanaURL: []byte(testGrafanaURL)}},
 			},
 		},
 		"NotATenant": {
 			reason: "Should return an error if the managed resource is not a Tenant.",
 			kube:   newFakeKube(),
-			sso:    defaultMockSSO(),
 			args: args{
 				ctx: context.Background(),
 				mg:  &fake.Managed{},
@@ -303,7 +429,6 @@ func TestCreate(t *testing.T) {
 				existing.SetUID("existing-uid")
 				return newFakeKube(existing)
 			}(),
-			sso: defaultMockSSO(),
 			args: args{
 				ctx: context.Background(),
 				mg: func() resource.Managed {
@@ -320,7 +445,7 @@ func TestCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{kube: tc.kube, sso: tc.sso, logger: logging.NewNopLogger()}
+			e := external{pc: testProviderConfig(), kube: tc.kube, orgs: defaultMockOrgs(), users: &mockUsers{logins: []string{"admin1"}}, locks: grafana.NewLocker(), logger: logging.NewNopLogger()}
 			got, err := e.Create(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -362,14 +487,12 @@ func TestUpdate(t *testing.T) {
 	cases := map[string]struct {
 		reason string
 		kube   client.Client
-		sso    *mockSSO
 		args   args
 		want   want
 	}{
 		"Success": {
-			reason: "Should sync changed spec to status, update timestamp, and sync Grafana.",
+			reason: "Should sync changed spec to status and update timestamp.",
 			kube:   newFakeKube(),
-			sso:    defaultMockSSO(),
 			args: args{
 				ctx: context.Background(),
 				mg: func() resource.Managed {
@@ -385,14 +508,14 @@ func TestUpdate(t *testing.T) {
 				}(),
 			},
 			want: want{
-				o: managed.ExternalUpdate{},
+				o: managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{keyTenantID: []byte("acme"), keyOrgID: []byte("org-2"), keyGrafanaURL: []byte(testGrafanaURL)}},
 			},
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{kube: tc.kube, sso: tc.sso, logger: logging.NewNopLogger()}
+			e := external{pc: testProviderConfig(), kube: tc.kube, orgs: defaultMockOrgs(), users: &mockUsers{}, locks: grafana.NewLocker(), recorder: event.NewNopRecorder(), logger: logging.NewNopLogger()}
 			got, err := e.Update(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -417,9 +540,138 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdateGrafanaSynced(t *testing.T) {
+	// Retention overrides of a ClusterProviderConfig need a namespace.
+	noNamespace := &apisv1alpha1.ClusterProviderConfig{}
+	noNamespace.Spec.RetentionOverrides = &apisv1alpha1.RetentionOverrides{Name: "overrides"}
+
+	cases := map[string]struct {
+		reason   string
+		pc       *providerConfig
+		usersErr error
+		wantErr  bool
+		want     corev1.ConditionStatus
+	}{
+		"Synced": {
+			reason: "Should report a successful sync.",
+			want:   corev1.ConditionTrue,
+		},
+		"Failed": {
+			reason:   "Should report a failed sync and return its error so that the managed reconciler backs off.",
+			usersErr: errors.New("boom"),
+			wantErr:  true,
+			want:     corev1.ConditionFalse,
+		},
+		"RetentionFailed": {
+			reason:  "Should report a failed write of the retention overrides and return its error.",
+			pc:      newProviderConfig(noNamespace),
+			wantErr: true,
+			want:    corev1.ConditionFalse,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tenantWithSpec("acme", "1", []string{"alice"}, v1alpha1.RetentionPolicy{})
+			cr.SetGeneration(3)
+			meta.SetExternalName(cr, "acme")
+
+			orgs := defaultMockOrgs()
+			orgs.usersErr = tc.usersErr
+			pc := tc.pc
+			if pc == nil {
+				pc = testProviderConfig()
+			}
+			e := external{pc: pc, kube: newFakeKube(), orgs: orgs, users: &mockUsers{logins: []string{"alice"}}, locks: grafana.NewLocker(), recorder: event.NewNopRecorder(), logger: logging.NewNopLogger()}
+			_, err := e.Update(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\ne.Update(...): error = %v, wantErr %v", tc.reason, err, tc.wantErr)
+			}
+
+			got := cr.GetCondition(v1alpha1.TypeGrafanaSynced)
+			if got.Status != tc.want || got.ObservedGeneration != 3 {
+				t.Errorf("\n%s\ne.Update(...): GrafanaSynced = %s at generation %d, want %s at generation 3", tc.reason, got.Status, got.ObservedGeneration, tc.want)
+			}
+			if err != nil && got.Message != err.Error() {
+				t.Errorf("\n%s\ne.Update(...): GrafanaSynced message = %q, want %q", tc.reason, got.Message, err.Error())
+			}
+			if cr.Status.AtProvider.LastSyncAttempt == nil {
+				t.Errorf("\n%s\ne.Update(...): expected the sync attempt to be recorded", tc.reason)
+			}
+		})
+	}
+}
+
+// recordedEvents implements event.Recorder, recording the reasons of events.
+type recordedEvents struct {
+	reasons []event.Reason
+}
+
+func (r *recordedEvents) Event(_ kruntime.Object, e event.Event) {
+	r.reasons = append(r.reasons, e.Reason)
+}
+
+func (r *recordedEvents) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func TestUpdateMovesOrg(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		usersErr   error
+		wantErr    bool
+		wantEvents []event.Reason
+		wantOrgID  string
+	}{
+		"Moved": {
+			reason:     "Should record the move and the new orgId.",
+			wantEvents: []event.Reason{reasonMovedOrg},
+			wantOrgID:  "2",
+		},
+		"Failed": {
+			reason:    "Should return the error and keep the previous orgId, so that the move is retried.",
+			usersErr:  errors.New("boom"),
+			wantErr:   true,
+			wantOrgID: "1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tenantWithSpec("acme", "2", []string{"alice"}, v1alpha1.RetentionPolicy{})
+			cr.SetUID("acme-uid")
+			cr.Spec.ForProvider.ViewerGroups = []string{"acme-viewers"}
+			cr.Status.AtProvider = v1alpha1.TenantObservation{TenantID: "acme", OrgID: "1", Admins: []string{"alice"}}
+
+			orgs := &mockOrgs{orgs: map[int64]string{1: "org-1", 2: "org-2"}, members: map[int64]string{1: grafana.RoleAdmin}, usersErr: tc.usersErr}
+			recorder := &recordedEvents{}
+			e := external{
+				pc:       testProviderConfig(),
+				kube:     newFakeKube(cr),
+				orgs:     orgs,
+				users:    &mockUsers{logins: []string{"alice"}},
+				locks:    grafana.NewLocker(),
+				recorder: recorder,
+				logger:   logging.NewNopLogger(),
+			}
+
+			_, err := e.Update(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\ne.Update(...): error = %v, wantErr %v", tc.reason, err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.wantEvents, recorder.reasons); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+			if got := cr.Status.AtProvider.OrgID; got != tc.wantOrgID {
+				t.Errorf("\n%s\ne.Update(...): status orgId = %q, want %q", tc.reason, got, tc.wantOrgID)
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	cr := tenantWithSpec("acme", "org-1", nil, v1alpha1.RetentionPolicy{})
-	e := external{kube: newFakeKube(), sso: defaultMockSSO(), logger: logging.NewNopLogger()}
+	e := external{pc: testProviderConfig(), kube: newFakeKube(), locks: grafana.NewLocker(), logger: logging.NewNopLogger()}
 	got, err := e.Delete(context.Background(), cr)
 	if err != nil {
 		t.Errorf("e.Delete(...): unexpected error: %v", err)
@@ -429,11 +681,384 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestSyncRetentionOverrides(t *testing.T) {
+	withRetention := func(name, tenantID, pcName string, r v1alpha1.RetentionPolicy) *v1alpha1.Tenant {
+		cr := tenantWithSpec(tenantID, "1", nil, r)
+		cr.SetName(name)
+		cr.SetNamespace("team-a")
+		cr.SetUID(types.UID(name + "-uid"))
+		cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: pcName}
+		return cr
+	}
+
+	a := withRetention("a", "a", "prod", v1alpha1.RetentionPolicy{Logs: "30d", Traces: "1w"})
+	b := withRetention("b", "b", "prod", v1alpha1.RetentionPolicy{Metrics: "1y"})
+	staging := withRetention("c", "c", "staging", v1alpha1.RetentionPolicy{Logs: "7d"})
+	// Another Tenant being deleted has its overrides dropped already.
+	gone := withRetention("d", "d", "prod", v1alpha1.RetentionPolicy{Logs: "1d"})
+	gone.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+	gone.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	cases := map[string]struct {
+		reason   string
+		kind     apisv1alpha1.RetentionOverridesKind
+		deleting bool
+		want     map[string]string
+	}{
+		"ConfigMap": {
+			reason: "Should render the retention of all tenants sharing the ProviderConfig to a ConfigMap.",
+			kind:   apisv1alpha1.RetentionOverridesKindConfigMap,
+			want: map[string]string{
+				retention.KeyLoki:      "overrides:\n  a:\n    retention_period: 30d\n",
+				retention.KeyMimir:     "overrides:\n  b:\n    compactor_blocks_retention_period: 1y\n",
+				retention.KeyTempo:     "overrides:\n  a:\n    compaction:\n      block_retention: 168h\n",
+				retention.KeyPyroscope: "overrides: {}\n",
+			},
+		},
+		"Secret": {
+			reason:   "Should render to a Secret, excluding the tenant being deleted.",
+			kind:     apisv1alpha1.RetentionOverridesKindSecret,
+			deleting: true,
+			want: map[string]string{
+				retention.KeyLoki:      "overrides: {}\n",
+				retention.KeyMimir:     "overrides:\n  b:\n    compactor_blocks_retention_period: 1y\n",
+				retention.KeyTempo:     "overrides: {}\n",
+				retention.KeyPyroscope: "overrides: {}\n",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &apisv1alpha1.ClusterProviderConfig{}
+			pc.SetName("prod")
+			pc.Spec.RetentionOverrides = &apisv1alpha1.RetentionOverrides{Kind: tc.kind, Name: "overrides", Namespace: "observability"}
+			kube := newFakeKube(a, b, staging, gone, pc)
+			e := external{
+				kube:   kube,
+				pc:     &providerConfig{obj: pc, spec: &pc.Spec, status: &pc.Status},
+				locks:  grafana.NewLocker(),
+				logger: logging.NewNopLogger(),
+			}
+
+			if err := e.syncRetentionOverrides(context.Background(), a, tc.deleting); err != nil {
+				t.Fatalf("\n%s\ne.syncRetentionOverrides(...): unexpected error: %v", tc.reason, err)
+			}
+
+			key := client.ObjectKey{Namespace: "observability", Name: "overrides"}
+			got := map[string]string{}
+			if tc.kind == apisv1alpha1.RetentionOverridesKindSecret {
+				s := &corev1.Secret{}
+				if err := kube.Get(context.Background(), key, s); err != nil {
+					t.Fatalf("kube.Get(...): unexpected error: %v", err)
+				}
+				for k, v := range s.Data {
+					got[k] = string(v)
+				}
+			} else {
+				cm := &corev1.ConfigMap{}
+				if err := kube.Get(context.Background(), key, cm); err != nil {
+					t.Fatalf("kube.Get(...): unexpected error: %v", err)
+				}
+				got = cm.Data
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.syncRetentionOverrides(...): -want overrides, +got overrides:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSyncRetentionOverridesUnchanged(t *testing.T) {
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{Logs: "30d"})
+	cr.SetUID("acme-uid")
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: apisv1alpha1.ClusterProviderConfigKind, Name: "prod"}
+	pc := &apisv1alpha1.ClusterProviderConfig{}
+	pc.SetName("prod")
+	pc.Spec.RetentionOverrides = &apisv1alpha1.RetentionOverrides{Name: "overrides", Namespace: "observability"}
+	kube := newFakeKube(cr, pc)
+	e := external{kube: kube, pc: &providerConfig{obj: pc, spec: &pc.Spec, status: &pc.Status}, locks: grafana.NewLocker(), logger: logging.NewNopLogger()}
+
+	key := client.ObjectKey{Namespace: "observability", Name: "overrides"}
+	versions := make([]string, 0, 2)
+	for range 2 {
+		if err := e.syncRetentionOverrides(context.Background(), cr, false); err != nil {
+			t.Fatalf("e.syncRetentionOverrides(...): unexpected error: %v", err)
+		}
+		cm := &corev1.ConfigMap{}
+		if err := kube.Get(context.Background(), key, cm); err != nil {
+			t.Fatalf("kube.Get(...): unexpected error: %v", err)
+		}
+		versions = append(versions, cm.GetResourceVersion())
+	}
+	if versions[0] != versions[1] {
+		t.Errorf("e.syncRetentionOverrides(...): rewrote unchanged overrides, resourceVersion %s -> %s", versions[0], versions[1])
+	}
+}
+
+func TestCreateOrg(t *testing.T) {
+	m := &mockOrgs{orgs: map[int64]string{}, members: map[int64]string{}}
+	cr := tenantWithSpec("acme", "", nil, v1alpha1.RetentionPolicy{})
+	cr.Spec.ForProvider.CreateOrg = true
+	cr.Spec.ForProvider.OrgName = "Acme Corp"
+	cr.Spec.ForProvider.ViewerGroups = []string{"acme-viewers"}
+	cr.SetUID("acme-uid")
+
+	e := external{pc: testProviderConfig(), kube: newFakeKube(cr), orgs: m, locks: grafana.NewLocker(), logger: logging.NewNopLogger()}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(map[int64]string{1: "Acme Corp"}, m.orgs); diff != "" {
+		t.Errorf("e.Create(...): -want orgs, +got orgs:\n%s", diff)
+	}
+	if cr.Status.AtProvider.OrgID != "1" || cr.Status.AtProvider.OrgName != "Acme Corp" {
+		t.Errorf("e.Create(...): status org = %q/%q, want 1/Acme Corp", cr.Status.AtProvider.OrgID, cr.Status.AtProvider.OrgName)
+	}
+	if !isUpToDate(cr, nil) {
+		t.Error("e.Create(...): expected tenant to be up to date after create")
+	}
+}
+
+func TestObserveDeletesOrg(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		policy      xpv1.DeletionPolicy
+		wantDeleted []int64
+	}{
+		"Delete": {
+			reason:      "Should delete the created organization when orgDeletionPolicy is Delete.",
+			policy:      xpv1.DeletionDelete,
+			wantDeleted: []int64{1},
+		},
+		"Orphan": {
+			reason: "Should keep the created organization when orgDeletionPolicy is Orphan.",
+			policy: xpv1.DeletionOrphan,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mockOrgs{orgs: map[int64]string{1: "Acme Corp"}}
+			cr := tenantWithSpec("acme", "", nil, v1alpha1.RetentionPolicy{})
+			cr.Spec.ForProvider.CreateOrg = true
+			cr.Spec.ForProvider.OrgName = "Acme Corp"
+			cr.Spec.ForProvider.OrgDeletionPolicy = tc.policy
+			cr.Status.AtProvider.OrgID = "1"
+			meta.SetExternalName(cr, "acme")
+			now := metav1.Now()
+			cr.SetDeletionTimestamp(&now)
+
+			e := external{pc: testProviderConfig(), kube: newFakeKube(), orgs: m, locks: grafana.NewLocker(), recorder: event.NewNopRecorder(), logger: logging.NewNopLogger()}
+			got, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if got.ResourceExists {
+				t.Errorf("\n%s\ne.Observe(...): expected ResourceExists false", tc.reason)
+			}
+			if diff := cmp.Diff(tc.wantDeleted, m.deleted); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeletionWaitsForOrgMapping(t *testing.T) {
+	failed := &apisv1alpha1.ProviderConfigStatus{OrgMappingSync: &apisv1alpha1.OrgMappingSync{Generation: 1, Tenants: map[string]int64{"acme-uid": 1}}}
+	failed.SetConditions(apisv1alpha1.OrgMappingSyncFailed(errors.New("boom")))
+
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		status      *apisv1alpha1.ProviderConfigStatus
+		wantExists  bool
+		wantErr     error
+		wantEvents  []event.Reason
+	}{
+		"Pending": {
+			reason:     "Should hold the finalizer while the last sync included the Tenant.",
+			status:     &apisv1alpha1.ProviderConfigStatus{OrgMappingSync: &apisv1alpha1.OrgMappingSync{Generation: 1, Tenants: map[string]int64{"acme-uid": 1}}},
+			wantExists: true,
+			wantErr:    errors.New(errMappingRemoval),
+		},
+		"NeverSynced": {
+			reason:     "Should hold the finalizer until the org_mapping was synced at least once.",
+			status:     &apisv1alpha1.ProviderConfigStatus{},
+			wantExists: true,
+			wantErr:    errors.New(errMappingRemoval),
+		},
+		"SyncFailed": {
+			reason:     "Should report why the org_mapping could not be synced.",
+			status:     failed,
+			wantExists: true,
+			wantErr:    errors.New(errMappingRemoval + ": boom"),
+		},
+		"Removed": {
+			reason:     "Should release the finalizer once a sync excluded the Tenant.",
+			status:     &apisv1alpha1.ProviderConfigStatus{OrgMappingSync: &apisv1alpha1.OrgMappingSync{Generation: 2, Tenants: map[string]int64{"other-uid": 1}}},
+			wantEvents: []event.Reason{reasonMappingRemoved},
+		},
+		"ForceDelete": {
+			reason:      "Should release the finalizer without confirmation when the force-delete annotation is set.",
+			annotations: map[string]string{v1alpha1.AnnotationKeyForceDelete: "true"},
+			status:      failed,
+			wantEvents:  []event.Reason{reasonForceDeleted},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+			cr.SetUID("acme-uid")
+			cr.SetAnnotations(tc.annotations)
+			meta.SetExternalName(cr, "acme")
+			now := metav1.Now()
+			cr.SetDeletionTimestamp(&now)
+
+			recorder := &recordedEvents{}
+			e := external{
+				kube:     newFakeKube(),
+				pc:       &providerConfig{obj: &apisv1alpha1.ClusterProviderConfig{}, spec: &apisv1alpha1.ProviderConfigSpec{}, status: tc.status},
+				locks:    grafana.NewLocker(),
+				recorder: recorder,
+				logger:   logging.NewNopLogger(),
+			}
+			got, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if got.ResourceExists != tc.wantExists {
+				t.Errorf("\n%s\ne.Observe(...): ResourceExists = %v, want %v", tc.reason, got.ResourceExists, tc.wantExists)
+			}
+			if diff := cmp.Diff(tc.wantEvents, recorder.reasons); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+			if !tc.wantExists {
+				return
+			}
+			if diff := cmp.Diff(v1alpha1.OrgMappingRemoving(), cr.GetCondition(v1alpha1.TypeOrgMappingSynced), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+			_, err = e.Delete(context.Background(), cr)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSyncAdmins(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		admins      []string
+		previous    []string
+		members     map[int64]string
+		usersErr    error
+		wantErr     bool
+		want        xpv1.Condition
+		wantMembers map[int64]string
+		wantAdmins  []string
+	}{
+		"NoAdmins": {
+			reason:      "Should neither touch Grafana nor set a condition when there are no admins.",
+			members:     map[int64]string{1: "Viewer"},
+			want:        xpv1.Condition{Type: v1alpha1.TypeAdminsProvisioned, Status: corev1.ConditionUnknown},
+			wantMembers: map[int64]string{1: "Viewer"},
+		},
+		"Provisioned": {
+			reason:      "Should grant the Admin role and report all admins as provisioned.",
+			admins:      []string{"alice"},
+			members:     map[int64]string{1: "Viewer"},
+			want:        v1alpha1.AdminsProvisioned(),
+			wantMembers: map[int64]string{1: "Admin"},
+			wantAdmins:  []string{"alice"},
+		},
+		"Removed": {
+			reason:      "Should remove admins dropped from the list from the org.",
+			admins:      []string{"alice"},
+			previous:    []string{"alice", "bob"},
+			members:     map[int64]string{1: "Admin", 2: "Admin"},
+			want:        v1alpha1.AdminsProvisioned(),
+			wantMembers: map[int64]string{1: "Admin"},
+			wantAdmins:  []string{"alice"},
+		},
+		"Kept": {
+			reason:      "Should keep admins that are still listed.",
+			admins:      []string{"alice", "bob"},
+			previous:    []string{"alice", "bob"},
+			members:     map[int64]string{1: "Admin", 2: "Admin"},
+			want:        v1alpha1.AdminsProvisioned(),
+			wantMembers: map[int64]string{1: "Admin", 2: "Admin"},
+			wantAdmins:  []string{"alice", "bob"},
+		},
+		"Failed": {
+			reason:      "Should keep the recorded admins when the sync fails, so that dropped admins are removed on retry.",
+			admins:      []string{"alice"},
+			previous:    []string{"alice", "bob"},
+			members:     map[int64]string{1: "Admin", 2: "Admin"},
+			usersErr:    errors.New("boom"),
+			wantErr:     true,
+			want:        xpv1.Condition{Type: v1alpha1.TypeAdminsProvisioned, Status: corev1.ConditionUnknown},
+			wantMembers: map[int64]string{1: "Admin", 2: "Admin"},
+			wantAdmins:  []string{"alice", "bob"},
+		},
+		"Unresolved": {
+			reason:      "Should report admins that are not Grafana users in the condition.",
+			admins:      []string{"alice", "mallory"},
+			members:     map[int64]string{1: "Admin"},
+			want:        v1alpha1.AdminsUnresolved([]string{"mallory"}),
+			wantMembers: map[int64]string{1: "Admin"},
+			wantAdmins:  []string{"alice", "mallory"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mockOrgs{orgs: map[int64]string{}, members: tc.members, usersErr: tc.usersErr}
+			cr := tenantWithSpec("acme", "1", tc.admins, v1alpha1.RetentionPolicy{})
+			cr.Status.AtProvider.Admins = tc.previous
+			e := external{pc: testProviderConfig(), orgs: m, users: &mockUsers{logins: []string{"alice", "bob"}}, logger: logging.NewNopLogger()}
+
+			if err := e.syncAdmins(cr); (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\ne.syncAdmins(...): error = %v, wantErr %v", tc.reason, err, tc.wantErr)
+			}
+			got := cr.GetCondition(v1alpha1.TypeAdminsProvisioned)
+			if diff := cmp.Diff(tc.want, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.syncAdmins(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantMembers, m.members); diff != "" {
+				t.Errorf("\n%s\ne.syncAdmins(...): -want members, +got members:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantAdmins, cr.Status.AtProvider.Admins); diff != "" {
+				t.Errorf("\n%s\ne.syncAdmins(...): -want status admins, +got status admins:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestIsUpToDate(t *testing.T) {
+	groupSets := groupSetIndex{
+		{Namespace: "team-a", Name: "platform"}: {AdminGroups: []string{"platform-admins", "sre"}},
+	}
+	withGroupSet := func(obs []string) *v1alpha1.Tenant {
+		cr := tenantWithSpec("acme", "org-1", nil, v1alpha1.RetentionPolicy{})
+		cr.SetNamespace("team-a")
+		cr.Spec.ForProvider.AdminGroups = []string{"platform-admins"}
+		cr.Spec.ForProvider.GroupSets = []string{"platform"}
+		cr.Status.AtProvider = v1alpha1.TenantObservation{
+			TenantID:    "acme",
+			OrgID:       "org-1",
+			AdminGroups: obs,
+		}
+		return cr
+	}
+
 	cases := map[string]struct {
-		reason string
-		cr     *v1alpha1.Tenant
-		want   bool
+		reason    string
+		cr        *v1alpha1.Tenant
+		groupSets groupSetIndex
+		want      bool
 	}{
 		"UpToDate": {
 			reason: "Should return true when all fields match.",
@@ -449,6 +1074,30 @@ func TestIsUpToDate(t *testing.T) {
 			}(),
 			want: true,
 		},
+		"ConnectionServiceAccountInSync": {
+			reason: "Should return true when the connection service account matches its defaults.",
+			cr: func() *v1alpha1.Tenant {
+				cr := tenantWithSpec("acme", "org-1", nil, v1alpha1.RetentionPolicy{})
+				cr.Spec.ForProvider.ConnectionServiceAccount = &v1alpha1.ConnectionServiceAccount{}
+				cr.Status.AtProvider = v1alpha1.TenantObservation{
+					TenantID:                 "acme",
+					OrgID:                    "org-1",
+					ConnectionServiceAccount: &v1alpha1.ServiceAccountObservation{ID: 3, OrgID: "org-1", Name: "orgmapper-acme", Role: grafana.RoleViewer},
+				}
+				return cr
+			}(),
+			want: true,
+		},
+		"ConnectionServiceAccountMissing": {
+			reason: "Should return false when the connection service account has not been created.",
+			cr: func() *v1alpha1.Tenant {
+				cr := tenantWithSpec("acme", "org-1", nil, v1alpha1.RetentionPolicy{})
+				cr.Spec.ForProvider.ConnectionServiceAccount = &v1alpha1.ConnectionServiceAccount{Role: grafana.RoleEditor}
+				cr.Status.AtProvider = v1alpha1.TenantObservation{TenantID: "acme", OrgID: "org-1"}
+				return cr
+			}(),
+			want: false,
+		},
 		"DifferentOrgID": {
 			reason: "Should return false when orgId differs.",
 			cr: func() *v1alpha1.Tenant {
@@ -559,11 +1208,43 @@ func TestIsUpToDate(t *testing.T) {
 			}(),
 			want: false,
 		},
+		"GroupSetInSync": {
+			reason:    "Should return true when status holds the groups of the Tenant and its GroupSets, without duplicates.",
+			cr:        withGroupSet([]string{"platform-admins", "sre"}),
+			groupSets: groupSets,
+			want:      true,
+		},
+		"GroupSetChanged": {
+			reason:    "Should return false when a referenced GroupSet grants groups not yet in status.",
+			cr:        withGroupSet([]string{"platform-admins"}),
+			groupSets: groupSets,
+			want:      false,
+		},
+		"GroupSetMissing": {
+			reason: "Should ignore a referenced GroupSet that does not exist.",
+			cr:     withGroupSet([]string{"platform-admins"}),
+			want:   true,
+		},
+		"GroupsReordered": {
+			reason: "Should ignore the order, surrounding whitespace and duplicates of groups and admins.",
+			cr: func() *v1alpha1.Tenant {
+				cr := tenantWithSpec("acme", "org-1", []string{"admin2", "admin1"}, v1alpha1.RetentionPolicy{})
+				cr.Spec.ForProvider.ViewerGroups = []string{"viewers-b", " viewers-a", "viewers-b"}
+				cr.Status.AtProvider = v1alpha1.TenantObservation{
+					TenantID:     "acme",
+					OrgID:        "org-1",
+					Admins:       []string{"admin1", "admin2"},
+					ViewerGroups: []string{"viewers-a", "viewers-b"},
+				}
+				return cr
+			}(),
+			want: true,
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := isUpToDate(tc.cr)
+			got := isUpToDate(tc.cr, tc.groupSets)
 			if got != tc.want {
 				t.Errorf("\n%s\nisUpToDate(...): want %v, got %v", tc.reason, tc.want, got)
 			}
@@ -571,6 +1252,60 @@ func TestIsUpToDate(t *testing.T) {
 	}
 }
 
+func TestEffectiveGroups(t *testing.T) {
+	groupSets := groupSetIndex{
+		{Namespace: "team-a", Name: "observers"}: {ViewerGroups: []string{"auditors", "support"}},
+		{Namespace: "team-a", Name: "platform"}:  {EditorGroups: []string{"sre"}, AdminGroups: []string{"platform-admins"}},
+		{Namespace: "team-b", Name: "platform"}:  {AdminGroups: []string{"other-admins"}},
+	}
+
+	cr := tenantWithSpec("acme", "1", nil, v1alpha1.RetentionPolicy{})
+	cr.SetNamespace("team-a")
+	cr.Spec.ForProvider.ViewerGroups = []string{"support"}
+	cr.Spec.ForProvider.GroupSets = []string{"observers", "platform", "missing"}
+
+	want := v1alpha1.GroupSetParameters{
+		ViewerGroups: []string{"auditors", "support"},
+		EditorGroups: []string{"sre"},
+		AdminGroups:  []string{"platform-admins"},
+	}
+	if diff := cmp.Diff(want, effectiveGroups(cr, groupSets)); diff != "" {
+		t.Errorf("effectiveGroups(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"support"}, cr.Spec.ForProvider.ViewerGroups); diff != "" {
+		t.Errorf("effectiveGroups(...): modified spec viewerGroups: -want, +got:\n%s", diff)
+	}
+}
+
+func TestReferencingTenants(t *testing.T) {
+	tenant := func(ns, name string, groupSets ...string) *v1alpha1.Tenant {
+		cr := tenantWithSpec(name, "1", nil, v1alpha1.RetentionPolicy{})
+		cr.SetNamespace(ns)
+		cr.SetName(name)
+		cr.Spec.ForProvider.GroupSets = groupSets
+		return cr
+	}
+	kube := newFakeKube(
+		tenant("team-a", "acme", "platform"),
+		tenant("team-a", "globex", "observers", "platform"),
+		tenant("team-a", "initech", "observers"),
+		tenant("team-b", "umbrella", "platform"),
+	)
+
+	gs := &v1alpha1.GroupSet{}
+	gs.SetNamespace("team-a")
+	gs.SetName("platform")
+
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "acme"}},
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "globex"}},
+	}
+	got := referencingTenants(kube, logging.NewNopLogger())(context.Background(), gs)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("referencingTenants(...): -want, +got:\n%s", diff)
+	}
+}
+
 func errNotTenantError() error {
 	return errors.New(errNotTenant)
 }
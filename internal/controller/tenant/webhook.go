@@ -0,0 +1,333 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-tenant-orgmapper-crossplane-io-v1alpha1-tenant,mutating=false,failurePolicy=fail,sideEffects=None,groups=tenant.orgmapper.crossplane.io,resources=tenants,verbs=create;update,versions=v1alpha1,name=tenants.tenant.orgmapper.crossplane.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-tenant-orgmapper-crossplane-io-v1alpha1-clustertenant,mutating=false,failurePolicy=fail,sideEffects=None,groups=tenant.orgmapper.crossplane.io,resources=clustertenants,verbs=create;update,versions=v1alpha1,name=clustertenants.tenant.orgmapper.crossplane.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-tenant-orgmapper-crossplane-io-v1alpha1-groupset,mutating=false,failurePolicy=fail,sideEffects=None,groups=tenant.orgmapper.crossplane.io,resources=groupsets,verbs=create;update,versions=v1alpha1,name=groupsets.tenant.orgmapper.crossplane.io,admissionReviewVersions=v1
+
+const errNotGroupSet = "object is not a GroupSet custom resource"
+
+// SetupWebhook adds validating admission webhooks for Tenants, ClusterTenants
+// and GroupSets to the manager's webhook server.
+func SetupWebhook(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Tenant{}).
+		WithValidator(&validator{kube: mgr.GetClient()}).
+		Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.ClusterTenant{}).
+		WithValidator(&validator{kube: mgr.GetClient()}).
+		Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.GroupSet{}).
+		WithValidator(&groupSetValidator{kube: mgr.GetClient()}).
+		Complete()
+}
+
+// validator rejects Tenants and ClusterTenants that would produce a conflicting or malformed
+// org_mapping, so that they never reach the controller.
+type validator struct {
+	kube client.Client
+}
+
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(tenantObject)
+	if !ok {
+		return nil, errors.New(errNotTenant)
+	}
+
+	groupSets, err := listGroupSets(ctx, v.kube)
+	if err != nil {
+		return nil, err
+	}
+	params := field.NewPath("spec", "forProvider")
+	errs := validateGroups(params, cr, groupSets)
+	errs = append(errs, validateServiceAccounts(params, cr)...)
+
+	tenants, err := listTenants(ctx, v.kube)
+	if err != nil {
+		return nil, err
+	}
+	errs = append(errs, validateUnique(params, cr, tenants)...)
+	return nil, errs.ToAggregate()
+}
+
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(tenantObject)
+	if !ok {
+		return nil, errors.New(errNotTenant)
+	}
+	cr, ok := newObj.(tenantObject)
+	if !ok {
+		return nil, errors.New(errNotTenant)
+	}
+
+	// Metadata updates, such as the managed reconciler setting the external
+	// name or removing its finalizer, must never be blocked. Since tenantId
+	// cannot change, its uniqueness was settled when the Tenant was created.
+	if cr.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(old.GetParameters(), cr.GetParameters()) {
+		return nil, nil
+	}
+
+	groupSets, err := listGroupSets(ctx, v.kube)
+	if err != nil {
+		return nil, err
+	}
+	params := field.NewPath("spec", "forProvider")
+	errs := validateImmutable(params, old, cr)
+	errs = append(errs, validateGroups(params, cr, groupSets)...)
+	errs = append(errs, validateServiceAccounts(params, cr)...)
+
+	// A Tenant moving to another org must not take over one in use.
+	if cr.GetParameters().OrgID != old.GetParameters().OrgID {
+		tenants, err := listTenants(ctx, v.kube)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, validateUniqueOrgID(params, cr, tenants)...)
+	}
+	return nil, errs.ToAggregate()
+}
+
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// groupSetValidator rejects GroupSets that would grant a group of a Tenant
+// referencing them more than one role, which the validator only checks when
+// the Tenant itself changes.
+type groupSetValidator struct {
+	kube client.Client
+}
+
+func (v *groupSetValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	gs, ok := obj.(*v1alpha1.GroupSet)
+	if !ok {
+		return nil, errors.New(errNotGroupSet)
+	}
+	return nil, v.validate(ctx, gs)
+}
+
+func (v *groupSetValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*v1alpha1.GroupSet)
+	if !ok {
+		return nil, errors.New(errNotGroupSet)
+	}
+	gs, ok := newObj.(*v1alpha1.GroupSet)
+	if !ok {
+		return nil, errors.New(errNotGroupSet)
+	}
+	// As for Tenants, metadata and status updates must never be blocked.
+	if gs.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(old.Spec.ForProvider, gs.Spec.ForProvider) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, gs)
+}
+
+func (v *groupSetValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate runs the group checks of every Tenant referencing a GroupSet, and
+// rejects the GroupSet if they report a conflict that the GroupSet
+// introduces. Conflicts the Tenant has without the GroupSet are left to the
+// Tenant's own validation.
+func (v *groupSetValidator) validate(ctx context.Context, gs *v1alpha1.GroupSet) error {
+	groupSets, err := listGroupSets(ctx, v.kube)
+	if err != nil {
+		return err
+	}
+	tenants, err := groupSetTenants(ctx, v.kube, gs)
+	if err != nil {
+		return err
+	}
+
+	key := client.ObjectKeyFromObject(gs)
+	without := maps.Clone(groupSets)
+	delete(without, key)
+	groupSets[key] = gs.Spec.ForProvider
+
+	params := field.NewPath("spec", "forProvider")
+	var errs field.ErrorList
+	for _, t := range tenants {
+		existing := map[string]bool{}
+		for _, e := range validateGroups(params, t, without) {
+			existing[e.Error()] = true
+		}
+		for _, e := range validateGroups(params, t, groupSets) {
+			if !existing[e.Error()] {
+				errs = append(errs, field.Forbidden(params, fmt.Sprintf("conflicts with Tenant %s: %s", t.GetName(), e.Error())))
+			}
+		}
+	}
+	return errs.ToAggregate()
+}
+
+// validateImmutable rejects changes to the tenantId, which identifies a Tenant
+// in the LGTM stack. The CRD enforces the same rule; the webhook repeats it so
+// that all rejections of an update are reported together.
+func validateImmutable(params *field.Path, old, cr tenantObject) field.ErrorList {
+	if cr.GetParameters().TenantID != old.GetParameters().TenantID {
+		return field.ErrorList{field.Forbidden(params.Child("tenantId"), "field is immutable")}
+	}
+	return nil
+}
+
+// validateGroups rejects group names that cannot be represented in
+// org_mapping and groups that are granted more than one role, either by the
+// Tenant itself or by the GroupSets it references. GroupSets that do not
+// exist yet grant no groups.
+func validateGroups(params *field.Path, cr tenantObject, groupSets groupSetIndex) field.ErrorList {
+	var errs field.ErrorList
+
+	// granted records the role each group is granted, and where it is listed.
+	type grant struct{ role, source string }
+	granted := map[string]grant{}
+
+	p := cr.GetParameters()
+	for _, r := range roleGroups(v1alpha1.GroupSetParameters{ViewerGroups: p.ViewerGroups, EditorGroups: p.EditorGroups, AdminGroups: p.AdminGroups}) {
+		for i, g := range r.groups {
+			path := params.Child(r.role).Index(i)
+			prev, seen := granted[strings.TrimSpace(g)]
+			switch {
+			case strings.TrimSpace(g) == "":
+				errs = append(errs, field.Invalid(path, g, "group name must not be empty"))
+			case strings.Contains(g, ","):
+				errs = append(errs, field.Invalid(path, g, "group name must not contain commas"))
+			case seen && prev.role != r.role:
+				errs = append(errs, field.Invalid(path, g, "group is also listed in "+prev.source))
+			default:
+				granted[strings.TrimSpace(g)] = grant{role: r.role, source: r.role}
+			}
+		}
+	}
+
+	for i, name := range p.GroupSets {
+		gs, ok := groupSets[types.NamespacedName{Namespace: cr.GetNamespace(), Name: name}]
+		if !ok {
+			continue
+		}
+		for _, r := range roleGroups(gs) {
+			for _, g := range r.groups {
+				g = strings.TrimSpace(g)
+				if prev, seen := granted[g]; seen && prev.role != r.role {
+					errs = append(errs, field.Invalid(params.Child("groupSets").Index(i), name, fmt.Sprintf("GroupSet lists group %q in %s, which is also listed in %s", g, r.role, prev.source)))
+					continue
+				}
+				granted[g] = grant{role: r.role, source: r.role + " of GroupSet " + name}
+			}
+		}
+	}
+	return errs
+}
+
+// validateServiceAccounts rejects serviceAccounts named like the connection
+// service account. Existing service accounts are adopted by name, so both
+// would manage the same Grafana service account, and removing either would
+// delete the account the other publishes a token for.
+func validateServiceAccounts(params *field.Path, cr tenantObject) field.ErrorList {
+	conn := connectionServiceAccountOf(cr)
+	if conn == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	for i, sa := range cr.GetParameters().ServiceAccounts {
+		if sa.Name == conn.name {
+			errs = append(errs, field.Invalid(params.Child("serviceAccounts").Index(i).Child("name"), sa.Name, "name is used by the connection service account"))
+		}
+	}
+	return errs
+}
+
+// roleGroup is the list of groups granted one role.
+type roleGroup struct {
+	role   string
+	groups []string
+}
+
+// roleGroups returns the groups per role, named after their field.
+func roleGroups(p v1alpha1.GroupSetParameters) []roleGroup {
+	return []roleGroup{
+		{role: "viewerGroups", groups: p.ViewerGroups},
+		{role: "editorGroups", groups: p.EditorGroups},
+		{role: "adminGroups", groups: p.AdminGroups},
+	}
+}
+
+// validateUnique rejects a tenantId used by any other Tenant, and an orgId
+// used by another Tenant of the same Grafana instance.
+func validateUnique(params *field.Path, cr tenantObject, tenants []tenantObject) field.ErrorList {
+	var errs field.ErrorList
+	for _, t := range tenants {
+		if isSameTenant(t, cr) {
+			continue
+		}
+		if t.GetParameters().TenantID == cr.GetParameters().TenantID {
+			errs = append(errs, field.Duplicate(params.Child("tenantId"), cr.GetParameters().TenantID))
+		}
+	}
+	return append(errs, validateUniqueOrgID(params, cr, tenants)...)
+}
+
+// validateUniqueOrgID rejects an orgId used by another Tenant of the same
+// Grafana instance.
+func validateUniqueOrgID(params *field.Path, cr tenantObject, tenants []tenantObject) field.ErrorList {
+	orgID := orgIDOf(cr)
+	if orgID == "" {
+		return nil
+	}
+	var errs field.ErrorList
+	for _, t := range tenants {
+		if isSameTenant(t, cr) {
+			continue
+		}
+		if orgIDOf(t) == orgID && providerConfigKeyOf(t) == providerConfigKeyOf(cr) {
+			errs = append(errs, field.Duplicate(params.Child("orgId"), orgID))
+		}
+	}
+	return errs
+}
+
+// isSameTenant reports whether a and b are the same Tenant or ClusterTenant.
+// The UID is not yet set when a Tenant is validated on create.
+func isSameTenant(a, b tenantObject) bool {
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && a.GetNamespace() == b.GetNamespace() && a.GetName() == b.GetName()
+}
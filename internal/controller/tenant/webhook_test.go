@@ -0,0 +1,352 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+
+	v1alpha1 "github.com/loafoe/provider-orgmapper/apis/tenant/v1alpha1"
+)
+
+func TestWebhook(t *testing.T) {
+	tenant := func(name, tenantID, orgID, pcName string, mod ...func(*v1alpha1.Tenant)) *v1alpha1.Tenant {
+		cr := tenantWithSpec(tenantID, orgID, nil, v1alpha1.RetentionPolicy{})
+		cr.SetName(name)
+		cr.SetNamespace("team-a")
+		cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: "ClusterProviderConfig", Name: pcName}
+		for _, fn := range mod {
+			fn(cr)
+		}
+		return cr
+	}
+	existing := tenant("existing", "acme", "1", "prod")
+
+	cluster := func(name, tenantID, orgID, pcName string) *v1alpha1.ClusterTenant {
+		cr := &v1alpha1.ClusterTenant{}
+		cr.SetName(name)
+		cr.Spec.ForProvider = v1alpha1.TenantParameters{TenantID: tenantID, OrgID: orgID}
+		cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: "ClusterProviderConfig", Name: pcName}
+		return cr
+	}
+
+	cases := map[string]struct {
+		reason  string
+		op      admissionv1.Operation
+		old     tenantObject
+		cr      tenantObject
+		allowed bool
+	}{
+		"ClusterTenantValid": {
+			reason:  "Should admit a ClusterTenant with a unique tenantId and orgId.",
+			op:      admissionv1.Create,
+			cr:      cluster("globex", "globex", "2", "prod"),
+			allowed: true,
+		},
+		"ClusterTenantDuplicateTenantID": {
+			reason: "Should reject a ClusterTenant whose tenantId is used by a Tenant.",
+			op:     admissionv1.Create,
+			cr:     cluster("acme", "acme", "2", "staging"),
+		},
+		"ClusterTenantDuplicateOrgID": {
+			reason: "Should reject a ClusterTenant whose orgId is used by a Tenant of the same ClusterProviderConfig.",
+			op:     admissionv1.Create,
+			cr:     cluster("globex", "globex", "1", "prod"),
+		},
+		"Valid": {
+			reason:  "Should admit a Tenant with a unique tenantId and orgId.",
+			op:      admissionv1.Create,
+			cr:      tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) { cr.Spec.ForProvider.ViewerGroups = []string{"oidc:viewers"} }),
+			allowed: true,
+		},
+		"DuplicateTenantID": {
+			reason: "Should reject a tenantId used by another Tenant.",
+			op:     admissionv1.Create,
+			cr:     tenant("new", "acme", "2", "staging"),
+		},
+		"DuplicateOrgID": {
+			reason: "Should reject an orgId used by another Tenant of the same Grafana.",
+			op:     admissionv1.Create,
+			cr:     tenant("new", "globex", "1", "prod"),
+		},
+		"OrgIDOfOtherGrafana": {
+			reason:  "Should admit an orgId used by a Tenant of another Grafana.",
+			op:      admissionv1.Create,
+			cr:      tenant("new", "globex", "1", "staging"),
+			allowed: true,
+		},
+		"EmptyGroup": {
+			reason: "Should reject an empty group name.",
+			op:     admissionv1.Create,
+			cr:     tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) { cr.Spec.ForProvider.EditorGroups = []string{" "} }),
+		},
+		"GroupWithComma": {
+			reason: "Should reject a group name containing a comma.",
+			op:     admissionv1.Create,
+			cr:     tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) { cr.Spec.ForProvider.AdminGroups = []string{"a,b"} }),
+		},
+		"GroupInTwoRoles": {
+			reason: "Should reject a group listed in two roles.",
+			op:     admissionv1.Create,
+			cr: tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) {
+				cr.Spec.ForProvider.ViewerGroups = []string{"team"}
+				cr.Spec.ForProvider.AdminGroups = []string{"team"}
+			}),
+		},
+		"GroupSetGroupInTwoRoles": {
+			reason: "Should reject a group the Tenant lists in another role than a GroupSet it references.",
+			op:     admissionv1.Create,
+			cr: tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) {
+				cr.Spec.ForProvider.ViewerGroups = []string{"sre"}
+				cr.Spec.ForProvider.GroupSets = []string{"platform"}
+			}),
+		},
+		"GroupSetsInTwoRoles": {
+			reason: "Should reject a group two referenced GroupSets grant different roles.",
+			op:     admissionv1.Create,
+			cr:     tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) { cr.Spec.ForProvider.GroupSets = []string{"observers", "platform"} }),
+		},
+		"GroupSetSameRole": {
+			reason: "Should admit a group the Tenant lists in the same role as a GroupSet it references, and ignore missing GroupSets.",
+			op:     admissionv1.Create,
+			cr: tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) {
+				cr.Spec.ForProvider.EditorGroups = []string{"sre"}
+				cr.Spec.ForProvider.GroupSets = []string{"platform", "missing"}
+			}),
+			allowed: true,
+		},
+		"ServiceAccountNamedLikeConnection": {
+			reason: "Should reject a service account named like the default connection service account.",
+			op:     admissionv1.Create,
+			cr: tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) {
+				cr.Spec.ForProvider.ConnectionServiceAccount = &v1alpha1.ConnectionServiceAccount{}
+				cr.Spec.ForProvider.ServiceAccounts = []v1alpha1.ServiceAccount{{Name: "ci"}, {Name: "orgmapper-globex"}}
+			}),
+		},
+		"ServiceAccountNamedLikeNamedConnection": {
+			reason: "Should reject a service account named like a named connection service account.",
+			op:     admissionv1.Update,
+			old:    existing,
+			cr: tenant("existing", "acme", "1", "prod", func(cr *v1alpha1.Tenant) {
+				cr.Spec.ForProvider.ConnectionServiceAccount = &v1alpha1.ConnectionServiceAccount{Name: "ci"}
+				cr.Spec.ForProvider.ServiceAccounts = []v1alpha1.ServiceAccount{{Name: "ci"}}
+			}),
+		},
+		"ServiceAccountsDistinct": {
+			reason: "Should admit service accounts named unlike the connection service account.",
+			op:     admissionv1.Create,
+			cr: tenant("new", "globex", "2", "prod", func(cr *v1alpha1.Tenant) {
+				cr.Spec.ForProvider.ConnectionServiceAccount = &v1alpha1.ConnectionServiceAccount{}
+				cr.Spec.ForProvider.ServiceAccounts = []v1alpha1.ServiceAccount{{Name: "orgmapper-acme"}}
+			}),
+			allowed: true,
+		},
+		"UpdateMutable": {
+			reason:  "Should admit changes to mutable fields.",
+			op:      admissionv1.Update,
+			old:     existing,
+			cr:      tenant("existing", "acme", "1", "prod", func(cr *v1alpha1.Tenant) { cr.Spec.ForProvider.ViewerGroups = []string{"viewers"} }),
+			allowed: true,
+		},
+		"UpdateTenantID": {
+			reason: "Should reject a changed tenantId.",
+			op:     admissionv1.Update,
+			old:    existing,
+			cr:     tenant("existing", "acme-2", "1", "prod"),
+		},
+		"UpdateOrgID": {
+			reason:  "Should admit moving a Tenant to an unused org.",
+			op:      admissionv1.Update,
+			old:     existing,
+			cr:      tenant("existing", "acme", "3", "prod"),
+			allowed: true,
+		},
+		"UpdateOrgIDInUse": {
+			reason: "Should reject moving a Tenant to an org used by another Tenant of the same Grafana.",
+			op:     admissionv1.Update,
+			old:    tenant("other", "globex", "2", "prod"),
+			cr:     tenant("other", "globex", "1", "prod"),
+		},
+		"UpdateDeleting": {
+			reason: "Should admit updates of a Tenant being deleted, such as finalizer removal.",
+			op:     admissionv1.Update,
+			old:    tenant("existing", "acme", "1", "prod", func(cr *v1alpha1.Tenant) { cr.Spec.ForProvider.AdminGroups = []string{"a,b"} }),
+			cr: tenant("existing", "acme", "1", "prod", func(cr *v1alpha1.Tenant) {
+				cr.Spec.ForProvider.AdminGroups = []string{"a,b"}
+				cr.SetDeletionTimestamp(&metav1.Time{})
+			}),
+			allowed: true,
+		},
+	}
+
+	scheme := kruntime.NewScheme()
+	_ = v1alpha1.SchemeBuilder.AddToScheme(scheme)
+	platform := &v1alpha1.GroupSet{}
+	platform.SetName("platform")
+	platform.SetNamespace("team-a")
+	platform.Spec.ForProvider = v1alpha1.GroupSetParameters{EditorGroups: []string{"sre"}}
+	observers := &v1alpha1.GroupSet{}
+	observers.SetName("observers")
+	observers.SetNamespace("team-a")
+	observers.Spec.ForProvider = v1alpha1.GroupSetParameters{ViewerGroups: []string{"auditors", "sre"}}
+
+	v := &validator{kube: newFakeKube(existing.DeepCopy(), platform, observers)}
+	tenantHandler := admission.WithCustomValidator(scheme, &v1alpha1.Tenant{}, v)
+	clusterHandler := admission.WithCustomValidator(scheme, &v1alpha1.ClusterTenant{}, v)
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := tenantHandler
+			if _, ok := tc.cr.(*v1alpha1.ClusterTenant); ok {
+				handler = clusterHandler
+			}
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tc.op,
+				Object:    kruntime.RawExtension{Raw: mustMarshal(t, tc.cr)},
+			}}
+			if tc.old != nil {
+				req.OldObject = kruntime.RawExtension{Raw: mustMarshal(t, tc.old)}
+			}
+
+			resp := handler.Handle(context.Background(), req)
+			if resp.Allowed != tc.allowed {
+				t.Errorf("\n%s\nHandle(...): allowed = %v, want %v: %s", tc.reason, resp.Allowed, tc.allowed, resp.Result.Message)
+			}
+		})
+	}
+}
+
+func TestGroupSetWebhook(t *testing.T) {
+	groupSet := func(name string, params v1alpha1.GroupSetParameters) *v1alpha1.GroupSet {
+		gs := &v1alpha1.GroupSet{}
+		gs.SetName(name)
+		gs.SetNamespace("team-a")
+		gs.Spec.ForProvider = params
+		return gs
+	}
+	tenant := func(name string, groupSets []string, mod func(*v1alpha1.TenantParameters)) *v1alpha1.Tenant {
+		cr := tenantWithSpec(name, "1", nil, v1alpha1.RetentionPolicy{})
+		cr.SetName(name)
+		cr.SetNamespace("team-a")
+		cr.Spec.ForProvider.GroupSets = groupSets
+		mod(&cr.Spec.ForProvider)
+		return cr
+	}
+	platform := groupSet("platform", v1alpha1.GroupSetParameters{EditorGroups: []string{"sre"}})
+	observers := groupSet("observers", v1alpha1.GroupSetParameters{ViewerGroups: []string{"auditors"}})
+	legacy := groupSet("legacy", v1alpha1.GroupSetParameters{ViewerGroups: []string{"support"}})
+	kube := newFakeKube(
+		platform, observers, legacy,
+		tenant("acme", []string{"platform", "observers"}, func(p *v1alpha1.TenantParameters) { p.ViewerGroups = []string{"viewers"} }),
+		// globex already grants a group two roles, which no GroupSet causes.
+		tenant("globex", []string{"legacy"}, func(p *v1alpha1.TenantParameters) {
+			p.ViewerGroups = []string{"team"}
+			p.AdminGroups = []string{"team"}
+		}),
+	)
+
+	cases := map[string]struct {
+		reason  string
+		op      admissionv1.Operation
+		old     *v1alpha1.GroupSet
+		gs      *v1alpha1.GroupSet
+		allowed bool
+	}{
+		"Valid": {
+			reason:  "Should admit a GroupSet that grants the groups of its Tenants a single role.",
+			op:      admissionv1.Update,
+			old:     platform,
+			gs:      groupSet("platform", v1alpha1.GroupSetParameters{EditorGroups: []string{"sre", "ops"}}),
+			allowed: true,
+		},
+		"ConflictsWithTenant": {
+			reason: "Should reject a GroupSet granting a group another role than a Tenant referencing it.",
+			op:     admissionv1.Update,
+			old:    platform,
+			gs:     groupSet("platform", v1alpha1.GroupSetParameters{AdminGroups: []string{"viewers"}}),
+		},
+		"ConflictsWithGroupSet": {
+			reason: "Should reject a GroupSet granting a group another role than a GroupSet referenced by the same Tenant.",
+			op:     admissionv1.Update,
+			old:    platform,
+			gs:     groupSet("platform", v1alpha1.GroupSetParameters{EditorGroups: []string{"auditors"}}),
+		},
+		"Unreferenced": {
+			reason:  "Should admit a GroupSet no Tenant references.",
+			op:      admissionv1.Create,
+			gs:      groupSet("unused", v1alpha1.GroupSetParameters{AdminGroups: []string{"viewers"}}),
+			allowed: true,
+		},
+		"ExistingConflict": {
+			reason:  "Should not reject a GroupSet for a conflict its Tenant has without it.",
+			op:      admissionv1.Update,
+			old:     legacy,
+			gs:      groupSet("legacy", v1alpha1.GroupSetParameters{ViewerGroups: []string{"support", "helpdesk"}}),
+			allowed: true,
+		},
+		"Deleting": {
+			reason: "Should admit metadata updates of a deleting GroupSet.",
+			op:     admissionv1.Update,
+			old:    platform,
+			gs: func() *v1alpha1.GroupSet {
+				gs := groupSet("platform", v1alpha1.GroupSetParameters{AdminGroups: []string{"viewers"}})
+				now := metav1.Now()
+				gs.SetDeletionTimestamp(&now)
+				return gs
+			}(),
+			allowed: true,
+		},
+	}
+
+	scheme := kruntime.NewScheme()
+	_ = v1alpha1.SchemeBuilder.AddToScheme(scheme)
+	handler := admission.WithCustomValidator(scheme, &v1alpha1.GroupSet{}, &groupSetValidator{kube: kube})
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tc.op,
+				Object:    kruntime.RawExtension{Raw: mustMarshal(t, tc.gs)},
+			}}
+			if tc.old != nil {
+				req.OldObject = kruntime.RawExtension{Raw: mustMarshal(t, tc.old)}
+			}
+
+			resp := handler.Handle(context.Background(), req)
+			if resp.Allowed != tc.allowed {
+				t.Errorf("\n%s\nHandle(...): allowed = %v, want %v: %s", tc.reason, resp.Allowed, tc.allowed, resp.Result.Message)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, obj any) []byte {
+	t.Helper()
+	b, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("json.Marshal(...): unexpected error: %v", err)
+	}
+	return b
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/grafana/grafana-openapi-client-go/client/orgs"
+	"github.com/grafana/grafana-openapi-client-go/client/users"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/pkg/errors"
+)
+
+// UserClient is the subset of the Grafana users API used by this package.
+type UserClient interface {
+	GetUserByLoginOrEmail(loginOrEmail string, opts ...users.ClientOption) (*users.GetUserByLoginOrEmailOK, error)
+}
+
+// OrgUserClient is the subset of the Grafana orgs API used to manage the
+// members of an organization.
+type OrgUserClient interface {
+	GetOrgByName(orgName string, opts ...orgs.ClientOption) (*orgs.GetOrgByNameOK, error)
+	GetOrgUsers(orgID int64, opts ...orgs.ClientOption) (*orgs.GetOrgUsersOK, error)
+	AddOrgUser(orgID int64, body *models.AddOrgUserCommand, opts ...orgs.ClientOption) (*orgs.AddOrgUserOK, error)
+	UpdateOrgUser(params *orgs.UpdateOrgUserParams, opts ...orgs.ClientOption) (*orgs.UpdateOrgUserOK, error)
+	RemoveOrgUser(userID int64, orgID int64, opts ...orgs.ClientOption) (*orgs.RemoveOrgUserOK, error)
+}
+
+// SyncOrgAdmins grants the Admin role in the given organization to each user
+// in admins, identified by login or email, and removes users that are in
+// previous but no longer in admins from the organization. Users whose role
+// was changed from Admin outside of this provider are left alone. Removed
+// users whose org_mapping groups still grant them access are added back by
+// Grafana the next time they sign in. The organization is identified by
+// numeric ID or by name. It returns the admins that could not be resolved to
+// a Grafana user.
+func SyncOrgAdmins(uc UserClient, oc OrgUserClient, org string, admins, previous []string) ([]string, error) {
+	orgID, err := resolveOrgID(oc, org)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := oc.GetOrgUsers(orgID)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list Grafana organization users")
+	}
+	roles := make(map[int64]string, len(members.Payload))
+	for _, m := range members.Payload {
+		roles[m.UserID] = m.Role
+	}
+
+	var unresolved []string
+	for _, a := range admins {
+		id, found, err := lookupUser(uc, a)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			unresolved = append(unresolved, a)
+			continue
+		}
+		role, member := roles[id]
+		switch {
+		case !member:
+			if _, err := oc.AddOrgUser(orgID, &models.AddOrgUserCommand{LoginOrEmail: a, Role: RoleAdmin}); err != nil {
+				return nil, errors.Wrapf(err, "cannot add %s to Grafana organization", a)
+			}
+		case role != RoleAdmin:
+			params := orgs.NewUpdateOrgUserParams().WithOrgID(orgID).WithUserID(id).WithBody(&models.UpdateOrgUserCommand{Role: RoleAdmin})
+			if _, err := oc.UpdateOrgUser(params); err != nil {
+				return nil, errors.Wrapf(err, "cannot grant Admin role to %s", a)
+			}
+		}
+	}
+
+	for _, p := range previous {
+		if slices.Contains(admins, p) {
+			continue
+		}
+		id, found, err := lookupUser(uc, p)
+		if err != nil {
+			return nil, err
+		}
+		if !found || roles[id] != RoleAdmin {
+			continue
+		}
+		if _, err := oc.RemoveOrgUser(id, orgID); err != nil && !hasCode(err, http.StatusNotFound) {
+			return nil, errors.Wrapf(err, "cannot remove %s from Grafana organization", p)
+		}
+	}
+
+	return unresolved, nil
+}
+
+// resolveOrgID returns the numeric ID of an organization given either its ID
+// or its name, both of which Grafana accepts in org_mapping.
+func resolveOrgID(oc OrgUserClient, org string) (int64, error) {
+	if id, err := strconv.ParseInt(org, 10, 64); err == nil {
+		return id, nil
+	}
+	resp, err := oc.GetOrgByName(org)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot get Grafana organization %q", org)
+	}
+	return resp.Payload.ID, nil
+}
+
+// lookupUser resolves a login or email to a Grafana user ID.
+func lookupUser(uc UserClient, loginOrEmail string) (int64, bool, error) {
+	resp, err := uc.GetUserByLoginOrEmail(loginOrEmail)
+	if err != nil {
+		if hasCode(err, http.StatusNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, errors.Wrapf(err, "cannot look up Grafana user %s", loginOrEmail)
+	}
+	return resp.Payload.ID, true, nil
+}
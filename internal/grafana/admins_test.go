@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-openapi-client-go/client/users"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/pkg/errors"
+)
+
+// mockUsers implements UserClient for testing, resolving logins to user IDs.
+type mockUsers struct {
+	logins map[string]int64
+	err    error
+}
+
+func (m *mockUsers) GetUserByLoginOrEmail(loginOrEmail string, _ ...users.ClientOption) (*users.GetUserByLoginOrEmailOK, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	id, ok := m.logins[loginOrEmail]
+	if !ok {
+		return nil, &users.GetUserByLoginOrEmailNotFound{}
+	}
+	return &users.GetUserByLoginOrEmailOK{Payload: &models.UserProfileDTO{ID: id, Login: loginOrEmail}}, nil
+}
+
+func TestSyncOrgAdmins(t *testing.T) {
+	logins := map[string]int64{"alice": 1, "bob": 2, "carol": 3}
+
+	cases := map[string]struct {
+		org            string
+		orgs           map[int64]string
+		members        map[int64]string
+		userErr        error
+		admins         []string
+		previous       []string
+		wantMembers    map[int64]string
+		wantUnresolved []string
+		wantErr        bool
+	}{
+		"AddsMissingAdmins": {
+			org:         "7",
+			admins:      []string{"alice", "bob"},
+			wantMembers: map[int64]string{1: RoleAdmin, 2: RoleAdmin},
+		},
+		"PromotesExistingMembers": {
+			org:         "7",
+			members:     map[int64]string{1: RoleViewer},
+			admins:      []string{"alice"},
+			wantMembers: map[int64]string{1: RoleAdmin},
+		},
+		"RemovesDroppedAdmins": {
+			org:         "7",
+			members:     map[int64]string{1: RoleAdmin, 2: RoleAdmin, 3: RoleEditor},
+			admins:      []string{"alice"},
+			previous:    []string{"alice", "bob"},
+			wantMembers: map[int64]string{1: RoleAdmin, 3: RoleEditor},
+		},
+		"KeepsListedAdmins": {
+			org:         "7",
+			members:     map[int64]string{1: RoleAdmin, 2: RoleAdmin},
+			admins:      []string{"alice", "bob"},
+			previous:    []string{"alice", "bob"},
+			wantMembers: map[int64]string{1: RoleAdmin, 2: RoleAdmin},
+		},
+		"KeepsDroppedNonAdmins": {
+			org:         "7",
+			members:     map[int64]string{2: RoleEditor},
+			previous:    []string{"bob", "carol"},
+			wantMembers: map[int64]string{2: RoleEditor},
+		},
+		"ReportsUnresolvedAdmins": {
+			org:            "7",
+			admins:         []string{"alice", "mallory"},
+			wantMembers:    map[int64]string{1: RoleAdmin},
+			wantUnresolved: []string{"mallory"},
+		},
+		"ResolvesOrgByName": {
+			org:         "acme",
+			orgs:        map[int64]string{7: "acme"},
+			admins:      []string{"carol"},
+			wantMembers: map[int64]string{3: RoleAdmin},
+		},
+		"UnknownOrgName": {
+			org:     "acme",
+			admins:  []string{"carol"},
+			wantErr: true,
+		},
+		"UserLookupError": {
+			org:     "7",
+			userErr: errors.New("forbidden"),
+			admins:  []string{"alice"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			members := map[int64]string{}
+			for id, role := range tc.members {
+				members[id] = role
+			}
+			oc := &mockOrgs{orgs: tc.orgs, members: map[int64]map[int64]string{7: members}, logins: logins}
+			uc := &mockUsers{logins: logins, err: tc.userErr}
+
+			unresolved, err := SyncOrgAdmins(uc, oc, tc.org, tc.admins, tc.previous)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("SyncOrgAdmins(...): expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SyncOrgAdmins(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantUnresolved, unresolved); diff != "" {
+				t.Errorf("SyncOrgAdmins(...): -want unresolved, +got unresolved:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantMembers, oc.members[7]); diff != "" {
+				t.Errorf("SyncOrgAdmins(...): -want members, +got members:\n%s", diff)
+			}
+		})
+	}
+}
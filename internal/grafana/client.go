@@ -17,13 +17,21 @@ limitations under the License.
 package grafana
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-openapi/strfmt"
 	goapi "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/pkg/transport"
 	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 // basicAuthCreds is the JSON structure for basic auth credentials.
@@ -32,31 +40,269 @@ type basicAuthCreds struct {
 	Password string `json:"password"`
 }
 
-// NewClient creates a Grafana HTTP API client from the given URL and raw credentials.
-// If creds is JSON with "username" and "password" keys, basic auth is used.
-// Otherwise creds is treated as a bearer token string.
-func NewClient(grafanaURL string, creds []byte) (*goapi.GrafanaHTTPAPI, error) {
+// clientCredentials is the JSON structure for OAuth2 client credentials.
+type clientCredentials struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
+
+// AuthMode determines how NewClient presents credentials to Grafana.
+type AuthMode string
+
+// Supported auth modes.
+const (
+	// AuthModeAuto uses basic auth if the credentials are a JSON object with
+	// username and password, and a service account token otherwise.
+	AuthModeAuto AuthMode = ""
+
+	// AuthModeServiceAccountToken sends the credentials as a bearer token.
+	AuthModeServiceAccountToken AuthMode = "ServiceAccountToken"
+
+	// AuthModeBasicAuth expects a JSON object with username and password.
+	AuthModeBasicAuth AuthMode = "BasicAuth"
+
+	// AuthModeOAuth2ClientCredentials expects a JSON object with clientId
+	// and clientSecret, which are exchanged for a bearer token at the token
+	// endpoint set with WithOAuth2.
+	AuthModeOAuth2ClientCredentials AuthMode = "OAuth2ClientCredentials"
+)
+
+// ClientOption configures the transport of a client created by NewClient.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	caBundle           []byte
+	clientCert         []byte
+	clientKey          []byte
+	insecureSkipVerify bool
+	timeout            time.Duration
+	retries            int
+	retryWait          time.Duration
+	authMode           AuthMode
+	tokenURL           string
+	scopes             []string
+	endpointParams     map[string]string
+	headers            map[string]string
+}
+
+// WithAuthMode makes NewClient interpret the credentials according to mode
+// instead of detecting their format.
+func WithAuthMode(mode AuthMode) ClientOption {
+	return func(o *clientOptions) {
+		o.authMode = mode
+	}
+}
+
+// WithOAuth2 sets the token endpoint, scopes and additional endpoint
+// parameters, such as an audience, of AuthModeOAuth2ClientCredentials.
+func WithOAuth2(tokenURL string, scopes []string, params map[string]string) ClientOption {
+	return func(o *clientOptions) {
+		o.tokenURL = tokenURL
+		o.scopes = scopes
+		o.endpointParams = params
+	}
+}
+
+// WithHeaders adds static headers, such as X-Grafana-Org-Id, to every
+// request.
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(o *clientOptions) {
+		o.headers = headers
+	}
+}
+
+// WithCABundle trusts the PEM-encoded CA certificates in bundle, in addition
+// to the system roots, to verify Grafana's serving certificate.
+func WithCABundle(bundle []byte) ClientOption {
+	return func(o *clientOptions) {
+		o.caBundle = bundle
+	}
+}
+
+// WithClientCertificate presents the given PEM-encoded certificate and key to
+// Grafana.
+func WithClientCertificate(cert, key []byte) ClientOption {
+	return func(o *clientOptions) {
+		o.clientCert = cert
+		o.clientKey = key
+	}
+}
+
+// WithInsecureSkipVerify disables verification of Grafana's serving
+// certificate.
+func WithInsecureSkipVerify() ClientOption {
+	return func(o *clientOptions) {
+		o.insecureSkipVerify = true
+	}
+}
+
+// WithTimeout bounds each API call, including its retries.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithRetries retries API calls that fail with a connection error, a 429 or a
+// 5xx response up to n times, waiting wait before each retry. A zero wait
+// backs off exponentially.
+func WithRetries(n int, wait time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.retries = n
+		o.retryWait = wait
+	}
+}
+
+// NewClient creates a Grafana HTTP API client from the given URL and raw
+// credentials. The format of creds depends on the auth mode; see AuthMode.
+func NewClient(grafanaURL string, creds []byte, opts ...ClientOption) (*goapi.GrafanaHTTPAPI, error) {
 	u, err := url.Parse(grafanaURL)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse grafana URL")
 	}
 
+	o := &clientOptions{}
+	for _, fn := range opts {
+		fn(o)
+	}
+	hc, err := newHTTPClient(o)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &goapi.TransportConfig{
 		Host:     u.Host,
 		BasePath: basePath(u.Path),
 		Schemes:  []string{u.Scheme},
+		Client:   hc,
 	}
+	if err := setAuth(cfg, o, creds); err != nil {
+		return nil, err
+	}
+
+	return goapi.NewHTTPClientWithConfig(strfmt.Default, cfg), nil
+}
 
+// setAuth configures cfg to present creds according to the auth mode.
+// Credentials that do not match the mode are rejected rather than sent in
+// another form.
+func setAuth(cfg *goapi.TransportConfig, o *clientOptions, creds []byte) error {
 	token := strings.TrimSpace(string(creds))
+	if token == "" {
+		return errors.New("credentials are empty")
+	}
 
-	var ba basicAuthCreds
-	if json.Unmarshal(creds, &ba) == nil && ba.Username != "" && ba.Password != "" {
-		cfg.BasicAuth = url.UserPassword(ba.Username, ba.Password)
-	} else {
+	switch o.authMode {
+	case AuthModeAuto:
+		if !strings.HasPrefix(token, "{") {
+			cfg.APIKey = token
+			return nil
+		}
+		ba, err := parseBasicAuth(creds)
+		if err != nil {
+			return errors.Wrap(err, "credentials look like JSON but are not basic auth credentials")
+		}
+		cfg.BasicAuth = ba
+	case AuthModeServiceAccountToken:
 		cfg.APIKey = token
+	case AuthModeBasicAuth:
+		ba, err := parseBasicAuth(creds)
+		if err != nil {
+			return err
+		}
+		cfg.BasicAuth = ba
+	case AuthModeOAuth2ClientCredentials:
+		var cc clientCredentials
+		if err := json.Unmarshal(creds, &cc); err != nil {
+			return errors.Wrap(err, "cannot parse OAuth2 client credentials")
+		}
+		if cc.ClientID == "" || cc.ClientSecret == "" {
+			return errors.New("OAuth2 client credentials must have a clientId and a clientSecret")
+		}
+		if o.tokenURL == "" {
+			return errors.New("OAuth2 token URL is not set")
+		}
+		cfg.Client.Transport = oauth2Transport(cfg.Client, o, cc)
+	default:
+		return errors.Errorf("unsupported auth mode %q", o.authMode)
 	}
+	return nil
+}
 
-	return goapi.NewHTTPClientWithConfig(strfmt.Default, cfg), nil
+// parseBasicAuth parses a JSON object with a username and password.
+func parseBasicAuth(creds []byte) (*url.Userinfo, error) {
+	var ba basicAuthCreds
+	if err := json.Unmarshal(creds, &ba); err != nil {
+		return nil, errors.Wrap(err, "cannot parse basic auth credentials")
+	}
+	if ba.Username == "" || ba.Password == "" {
+		return nil, errors.New("basic auth credentials must have a username and a password")
+	}
+	return url.UserPassword(ba.Username, ba.Password), nil
+}
+
+// oauth2Transport wraps the transport of hc so that every request carries a
+// bearer token obtained with the client credentials flow. Tokens are fetched
+// through the TLS-configured transport underneath the retries and static
+// headers of hc, which are meant for Grafana rather than the token endpoint.
+func oauth2Transport(hc *http.Client, o *clientOptions, cc clientCredentials) http.RoundTripper {
+	params := url.Values{}
+	for k, v := range o.endpointParams {
+		params.Set(k, v)
+	}
+	cfg := &clientcredentials.Config{
+		ClientID:       cc.ClientID,
+		ClientSecret:   cc.ClientSecret,
+		TokenURL:       o.tokenURL,
+		Scopes:         o.scopes,
+		EndpointParams: params,
+	}
+	base := hc.Transport
+	if rt, ok := base.(*transport.RetryableTransport); ok {
+		base = rt.Transport
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: base, Timeout: hc.Timeout})
+	return &oauth2.Transport{Source: cfg.TokenSource(ctx), Base: hc.Transport}
+}
+
+// newHTTPClient builds the HTTP client used to call Grafana. The API client
+// applies TLS settings to the process-wide http.DefaultTransport, so each
+// client gets its own transport instead.
+func newHTTPClient(o *clientOptions) (*http.Client, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.insecureSkipVerify, //nolint:gosec // Opt-in for development.
+	}
+	if len(o.caBundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(o.caBundle) {
+			return nil, errors.New("cannot parse CA bundle: no PEM-encoded certificates found")
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if len(o.clientCert) > 0 || len(o.clientKey) > 0 {
+		cert, err := tls.X509KeyPair(o.clientCert, o.clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse client certificate")
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = tlsCfg
+
+	return &http.Client{
+		Timeout: o.timeout,
+		Transport: &transport.RetryableTransport{
+			Transport:    tr,
+			NumRetries:   o.retries,
+			RetryTimeout: o.retryWait,
+			HTTPHeaders:  o.headers,
+		},
+	}, nil
 }
 
 // basePath ensures the path ends with /api.
@@ -17,13 +17,28 @@ limitations under the License.
 package grafana
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
 	cases := map[string]struct {
 		url     string
 		creds   []byte
+		opts    []ClientOption
 		wantErr bool
 	}{
 		"TokenAuth": {
@@ -47,11 +62,45 @@ func TestNewClient(t *testing.T) {
 			creds:   []byte("token"),
 			wantErr: true,
 		},
+		"EmptyCredentials": {
+			url:     "https://grafana.example.com",
+			creds:   []byte(" \n"),
+			wantErr: true,
+		},
+		"MalformedBasicAuth": {
+			url:     "https://grafana.example.com",
+			creds:   []byte(`{"user":"admin","password":"secret"}`),
+			wantErr: true,
+		},
+		"TokenInBasicAuthMode": {
+			url:     "https://grafana.example.com",
+			creds:   []byte("glsa_xxxxxxxxxxxx"),
+			opts:    []ClientOption{WithAuthMode(AuthModeBasicAuth)},
+			wantErr: true,
+		},
+		"OAuth2WithoutTokenURL": {
+			url:     "https://grafana.example.com",
+			creds:   []byte(`{"clientId":"provider","clientSecret":"secret"}`),
+			opts:    []ClientOption{WithAuthMode(AuthModeOAuth2ClientCredentials)},
+			wantErr: true,
+		},
+		"OAuth2WithoutClientSecret": {
+			url:     "https://grafana.example.com",
+			creds:   []byte(`{"clientId":"provider"}`),
+			opts:    []ClientOption{WithAuthMode(AuthModeOAuth2ClientCredentials), WithOAuth2("https://idp.example.com/token", nil, nil)},
+			wantErr: true,
+		},
+		"UnsupportedAuthMode": {
+			url:     "https://grafana.example.com",
+			creds:   []byte("token"),
+			opts:    []ClientOption{WithAuthMode("Kerberos")},
+			wantErr: true,
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			c, err := NewClient(tc.url, tc.creds)
+			c, err := NewClient(tc.url, tc.creds, tc.opts...)
 			if tc.wantErr {
 				if err == nil {
 					t.Error("NewClient(...): expected error, got nil")
@@ -69,6 +118,105 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientAuth(t *testing.T) {
+	cases := map[string]struct {
+		creds         []byte
+		opts          func(tokenURL string) []ClientOption
+		wantAuth      string
+		wantHeader    string
+		wantTokenReqs int32
+	}{
+		"ServiceAccountToken": {
+			creds: []byte("glsa_xxxxxxxxxxxx\n"),
+			opts: func(_ string) []ClientOption {
+				return []ClientOption{WithAuthMode(AuthModeServiceAccountToken)}
+			},
+			wantAuth: "Bearer glsa_xxxxxxxxxxxx",
+		},
+		"BasicAuth": {
+			creds: []byte(`{"username":"admin","password":"secret"}`),
+			opts: func(_ string) []ClientOption {
+				return []ClientOption{WithAuthMode(AuthModeBasicAuth)}
+			},
+			wantAuth: "Basic YWRtaW46c2VjcmV0",
+		},
+		"OAuth2ClientCredentials": {
+			creds: []byte(`{"clientId":"provider","clientSecret":"secret"}`),
+			opts: func(tokenURL string) []ClientOption {
+				return []ClientOption{WithAuthMode(AuthModeOAuth2ClientCredentials), WithOAuth2(tokenURL, []string{"grafana"}, nil)}
+			},
+			wantAuth:      "Bearer issued-token",
+			wantTokenReqs: 1,
+		},
+		"Headers": {
+			creds: []byte("token"),
+			opts: func(_ string) []ClientOption {
+				return []ClientOption{WithHeaders(map[string]string{"X-Grafana-Org-Id": "2"})}
+			},
+			wantAuth:   "Bearer token",
+			wantHeader: "2",
+		},
+		"OAuth2WithHeaders": {
+			creds: []byte(`{"clientId":"provider","clientSecret":"secret"}`),
+			opts: func(tokenURL string) []ClientOption {
+				return []ClientOption{
+					WithAuthMode(AuthModeOAuth2ClientCredentials), WithOAuth2(tokenURL, nil, nil),
+					WithHeaders(map[string]string{"X-Grafana-Org-Id": "2"}),
+				}
+			},
+			wantAuth:      "Bearer issued-token",
+			wantHeader:    "2",
+			wantTokenReqs: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var tokenReqs atomic.Int32
+			var gotAuth, gotHeader, gotTokenHeader string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+				tokenReqs.Add(1)
+				gotTokenHeader = r.Header.Get("X-Grafana-Org-Id")
+				if id, secret, _ := r.BasicAuth(); id != "provider" || secret != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"access_token":"issued-token","token_type":"Bearer","expires_in":3600}`))
+			})
+			mux.HandleFunc("/api/orgs/1", func(w http.ResponseWriter, r *http.Request) {
+				gotAuth = r.Header.Get("Authorization")
+				gotHeader = r.Header.Get("X-Grafana-Org-Id")
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":1,"name":"org-1"}`))
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			c, err := NewClient(srv.URL, tc.creds, tc.opts(srv.URL+"/token")...)
+			if err != nil {
+				t.Fatalf("NewClient(...): unexpected error: %v", err)
+			}
+			if _, err := c.Orgs.GetOrgByID(1); err != nil {
+				t.Fatalf("GetOrgByID(...): unexpected error: %v", err)
+			}
+			if gotAuth != tc.wantAuth {
+				t.Errorf("Authorization: got %q, want %q", gotAuth, tc.wantAuth)
+			}
+			if gotHeader != tc.wantHeader {
+				t.Errorf("X-Grafana-Org-Id: got %q, want %q", gotHeader, tc.wantHeader)
+			}
+			if gotTokenHeader != "" {
+				t.Errorf("X-Grafana-Org-Id sent to the token endpoint: %q", gotTokenHeader)
+			}
+			if got := tokenReqs.Load(); got != tc.wantTokenReqs {
+				t.Errorf("token endpoint called %d times, want %d", got, tc.wantTokenReqs)
+			}
+		})
+	}
+}
+
 func TestBasePath(t *testing.T) {
 	cases := map[string]struct {
 		path string
@@ -91,3 +239,159 @@ func TestBasePath(t *testing.T) {
 		})
 	}
 }
+
+// newClientCert returns a self-signed PEM-encoded client certificate and key,
+// and a pool that trusts it.
+func newClientCert(t *testing.T) (certPEM, keyPEM []byte, pool *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey(...): %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "provider-orgmapper"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate(...): %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey(...): %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		pool
+}
+
+func TestNewClientTransport(t *testing.T) {
+	clientCert, clientKey, clientCAs := newClientCert(t)
+
+	cases := map[string]struct {
+		// handler serves GET /api/orgs/1; calls counts its invocations.
+		handler    func(calls int32, w http.ResponseWriter)
+		clientAuth bool
+		opts       func(srv *httptest.Server) []ClientOption
+		wantErr    bool
+		wantCalls  int32
+	}{
+		"UntrustedCA": {
+			opts:    func(_ *httptest.Server) []ClientOption { return nil },
+			wantErr: true,
+		},
+		"CABundle": {
+			opts: func(srv *httptest.Server) []ClientOption {
+				return []ClientOption{WithCABundle(serverCAPEM(srv))}
+			},
+			wantCalls: 1,
+		},
+		"InsecureSkipVerify": {
+			opts:      func(_ *httptest.Server) []ClientOption { return []ClientOption{WithInsecureSkipVerify()} },
+			wantCalls: 1,
+		},
+		"MissingClientCertificate": {
+			clientAuth: true,
+			opts: func(srv *httptest.Server) []ClientOption {
+				return []ClientOption{WithCABundle(serverCAPEM(srv))}
+			},
+			wantErr: true,
+		},
+		"ClientCertificate": {
+			clientAuth: true,
+			opts: func(srv *httptest.Server) []ClientOption {
+				return []ClientOption{WithCABundle(serverCAPEM(srv)), WithClientCertificate(clientCert, clientKey)}
+			},
+			wantCalls: 1,
+		},
+		"Timeout": {
+			handler: func(_ int32, w http.ResponseWriter) {
+				time.Sleep(200 * time.Millisecond)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":1,"name":"org-1"}`))
+			},
+			opts: func(_ *httptest.Server) []ClientOption {
+				return []ClientOption{WithInsecureSkipVerify(), WithTimeout(50 * time.Millisecond)}
+			},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		"Retries": {
+			handler: func(calls int32, w http.ResponseWriter) {
+				if calls == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":1,"name":"org-1"}`))
+			},
+			opts: func(_ *httptest.Server) []ClientOption {
+				return []ClientOption{WithInsecureSkipVerify(), WithRetries(2, time.Millisecond)}
+			},
+			wantCalls: 2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				n := calls.Add(1)
+				if tc.handler != nil {
+					tc.handler(n, w)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":1,"name":"org-1"}`))
+			}))
+			// Rejected handshakes are expected; keep them out of the test output.
+			srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+			if tc.clientAuth {
+				srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+			}
+			srv.StartTLS()
+			defer srv.Close()
+
+			c, err := NewClient(srv.URL, []byte("token"), tc.opts(srv)...)
+			if err != nil {
+				t.Fatalf("NewClient(...): unexpected error: %v", err)
+			}
+			_, err = c.Orgs.GetOrgByID(1)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("GetOrgByID(...): got error %v, want error %v", err, tc.wantErr)
+			}
+			if got := calls.Load(); got != tc.wantCalls {
+				t.Errorf("GetOrgByID(...): server called %d times, want %d", got, tc.wantCalls)
+			}
+		})
+	}
+}
+
+// serverCAPEM returns the PEM-encoded certificate of a TLS test server.
+func serverCAPEM(srv *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+}
+
+func TestNewClientInvalidTLS(t *testing.T) {
+	cases := map[string][]ClientOption{
+		"CABundle":          {WithCABundle([]byte("not a certificate"))},
+		"ClientCertificate": {WithClientCertificate([]byte("cert"), []byte("key"))},
+	}
+
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewClient("https://grafana.example.com", []byte("token"), opts...); err == nil {
+				t.Error("NewClient(...): expected error, got nil")
+			}
+		})
+	}
+}
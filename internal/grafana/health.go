@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana-openapi-client-go/client/health"
+	"github.com/pkg/errors"
+)
+
+// HealthClient is the subset of the Grafana health API used by this package.
+type HealthClient interface {
+	GetHealth(opts ...health.ClientOption) (*health.GetHealthOK, error)
+}
+
+// CheckHealth probes a Grafana instance and returns its version. The health
+// endpoint does not require authentication, so the SSO settings of each
+// provider are read as well to verify the credentials and permissions. A
+// provider that is not configured yet is healthy, since the org_mapping sync
+// creates its settings.
+func CheckHealth(hc HealthClient, ssoc SSOClient, providers ...string) (string, error) {
+	resp, err := hc.GetHealth()
+	if err != nil {
+		return "", errors.Wrap(err, "cannot get Grafana health")
+	}
+	var version string
+	if resp.Payload != nil {
+		version = resp.Payload.Version
+	}
+
+	for _, p := range providers {
+		_, err := ssoc.GetProviderSettings(p)
+		switch {
+		case err == nil, IsNotFound(err):
+		case hasCode(err, http.StatusUnauthorized):
+			return version, errors.Wrap(err, "Grafana rejected the credentials")
+		case hasCode(err, http.StatusForbidden):
+			return version, errors.Wrapf(err, "credentials are not allowed to read the SSO settings of provider %s", p)
+		default:
+			return version, errors.Wrapf(err, "cannot get SSO settings of provider %s", p)
+		}
+	}
+	return version, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-openapi-client-go/client/health"
+	"github.com/grafana/grafana-openapi-client-go/client/sso_settings"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/pkg/errors"
+)
+
+// mockHealth implements HealthClient for testing.
+type mockHealth struct {
+	resp *health.GetHealthOK
+	err  error
+}
+
+func (m *mockHealth) GetHealth(_ ...health.ClientOption) (*health.GetHealthOK, error) {
+	return m.resp, m.err
+}
+
+func TestCheckHealth(t *testing.T) {
+	healthy := &mockHealth{resp: &health.GetHealthOK{Payload: &models.HealthResponse{Database: "ok", Version: "11.3.0"}}}
+
+	cases := map[string]struct {
+		hc          HealthClient
+		ssoc        SSOClient
+		wantVersion string
+		wantErr     bool
+	}{
+		"Healthy": {
+			hc:          healthy,
+			ssoc:        &mockSSO{getResp: &sso_settings.GetProviderSettingsOK{}},
+			wantVersion: "11.3.0",
+		},
+		"Unreachable": {
+			hc:      &mockHealth{err: errors.New("connection refused")},
+			ssoc:    &mockSSO{},
+			wantErr: true,
+		},
+		"ProviderNotConfigured": {
+			hc:          healthy,
+			ssoc:        &mockSSO{getErr: sso_settings.NewGetProviderSettingsNotFound()},
+			wantVersion: "11.3.0",
+		},
+		"Forbidden": {
+			hc:          healthy,
+			ssoc:        &mockSSO{getErr: sso_settings.NewGetProviderSettingsForbidden()},
+			wantVersion: "11.3.0",
+			wantErr:     true,
+		},
+		"Unauthorized": {
+			hc:          healthy,
+			ssoc:        &mockSSO{getErr: sso_settings.NewGetProviderSettingsUnauthorized()},
+			wantVersion: "11.3.0",
+			wantErr:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			version, err := CheckHealth(tc.hc, tc.ssoc, DefaultSSOProvider)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("CheckHealth(...): got error %v, want error %v", err, tc.wantErr)
+			}
+			if version != tc.wantVersion {
+				t.Errorf("CheckHealth(...): got version %q, want %q", version, tc.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import "sync"
+
+// Locker serializes read-modify-write cycles per key. The Tenant controllers
+// use it to serialize writes of the retention overrides rendered from all
+// Tenants of a ProviderConfig, so that concurrent reconciles never write a
+// rendering that misses another Tenant's changes. The locks are held in
+// memory, so they do not exclude a second replica of the provider; only
+// leader election (--leader-election) does.
+type Locker struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewLocker returns a Locker with no held locks.
+func NewLocker() *Locker {
+	return &Locker{locks: map[string]*sync.Mutex{}}
+}
+
+// Lock blocks until the lock for key is acquired and returns a function that
+// releases it.
+func (l *Locker) Lock(key string) func() {
+	l.mu.Lock()
+	m, ok := l.locks[key]
+	if !ok {
+		m = &sync.Mutex{}
+		l.locks[key] = m
+	}
+	l.mu.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
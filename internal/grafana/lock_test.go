@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-openapi-client-go/client/sso_settings"
+	"github.com/grafana/grafana-openapi-client-go/models"
+)
+
+// fakeSSO is a goroutine-safe SSOClient that stores settings in memory. Reads
+// are delayed so that unserialized read-modify-write cycles interleave.
+type fakeSSO struct {
+	mu       sync.Mutex
+	settings map[string]any
+}
+
+func (f *fakeSSO) GetProviderSettings(_ string, _ ...sso_settings.ClientOption) (*sso_settings.GetProviderSettingsOK, error) {
+	f.mu.Lock()
+	s := maps.Clone(f.settings)
+	f.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	return &sso_settings.GetProviderSettingsOK{Payload: &models.GetProviderSettingsOKBody{Settings: s}}, nil
+}
+
+func (f *fakeSSO) UpdateProviderSettings(_ string, body *models.UpdateProviderSettingsParamsBody, _ ...sso_settings.ClientOption) (*sso_settings.UpdateProviderSettingsNoContent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.settings = maps.Clone(body.Settings.(map[string]any))
+	return &sso_settings.UpdateProviderSettingsNoContent{}, nil
+}
+
+func TestLockerSerializesSync(t *testing.T) {
+	const n = 20
+
+	sso := &fakeSSO{settings: map[string]any{}}
+	l := NewLocker()
+
+	want := make([]string, 0, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		tm := TenantMapping{OrgID: fmt.Sprint(i), ViewerGroups: []string{fmt.Sprintf("team-%d", i)}}
+		want = append(want, BuildOrgMapping([]TenantMapping{tm}))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := l.Lock("grafana")
+			defer unlock()
+			// Each writer only owns its own entry, so every entry must survive
+			// if the read-modify-write cycles are serialized.
+			if _, err := SyncOrgMapping(context.Background(), sso, []TenantMapping{tm}, WithOwnedEntries(nil)); err != nil {
+				t.Errorf("SyncOrgMapping(...): unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := SplitOrgMapping(sso.settings["orgMapping"].(string))
+	sort.Strings(got)
+	sort.Strings(want)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("concurrent SyncOrgMapping(...): -want entries, +got entries:\n%s", diff)
+	}
+}
+
+func TestLockerIndependentKeys(t *testing.T) {
+	l := NewLocker()
+	unlockA := l.Lock("a")
+	defer unlockA()
+
+	done := make(chan struct{})
+	go func() {
+		l.Lock("b")()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Lock(\"b\") blocked while only \"a\" was held")
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana-openapi-client-go/client/orgs"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/pkg/errors"
+)
+
+// OrgClient is the subset of the Grafana orgs API used by this package.
+type OrgClient interface {
+	OrgUserClient
+	CreateOrg(body *models.CreateOrgCommand, opts ...orgs.ClientOption) (*orgs.CreateOrgOK, error)
+	GetOrgByID(orgID int64, opts ...orgs.ClientOption) (*orgs.GetOrgByIDOK, error)
+	UpdateOrg(orgID int64, body *models.UpdateOrgForm, opts ...orgs.ClientOption) (*orgs.UpdateOrgOK, error)
+	DeleteOrgByID(orgID int64, opts ...orgs.ClientOption) (*orgs.DeleteOrgByIDOK, error)
+}
+
+// EnsureOrg makes sure a Grafana organization with the given name exists and
+// returns its ID. If orgID refers to an existing organization it is renamed
+// when necessary; otherwise an organization with the given name is looked up
+// and created if missing.
+func EnsureOrg(oc OrgClient, orgID, name string) (string, error) {
+	if orgID != "" {
+		id, err := parseOrgID(orgID)
+		if err != nil {
+			return "", err
+		}
+		resp, err := oc.GetOrgByID(id)
+		switch {
+		case err == nil:
+			if resp.Payload.Name != name {
+				if _, err := oc.UpdateOrg(id, &models.UpdateOrgForm{Name: name}); err != nil {
+					return "", errors.Wrap(err, "cannot rename Grafana organization")
+				}
+			}
+			return orgID, nil
+		case !hasCode(err, http.StatusNotFound):
+			return "", errors.Wrap(err, "cannot get Grafana organization")
+		}
+	}
+
+	resp, err := oc.GetOrgByName(name)
+	if err == nil {
+		return strconv.FormatInt(resp.Payload.ID, 10), nil
+	}
+	if !hasCode(err, http.StatusNotFound) {
+		return "", errors.Wrap(err, "cannot get Grafana organization")
+	}
+
+	created, err := oc.CreateOrg(&models.CreateOrgCommand{Name: name})
+	if err != nil {
+		return "", errors.Wrap(err, "cannot create Grafana organization")
+	}
+	if created.Payload == nil || created.Payload.OrgID == nil {
+		return "", errors.New("Grafana did not return the ID of the created organization")
+	}
+	return strconv.FormatInt(*created.Payload.OrgID, 10), nil
+}
+
+// OrgExists reports whether the Grafana organization with the given ID exists.
+func OrgExists(oc OrgClient, orgID string) (bool, error) {
+	id, err := parseOrgID(orgID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := oc.GetOrgByID(id); err != nil {
+		if hasCode(err, http.StatusNotFound) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "cannot get Grafana organization")
+	}
+	return true, nil
+}
+
+// DeleteOrg deletes the Grafana organization with the given ID. Deleting an
+// organization that does not exist is not an error.
+func DeleteOrg(oc OrgClient, orgID string) error {
+	id, err := parseOrgID(orgID)
+	if err != nil {
+		return err
+	}
+	if _, err := oc.DeleteOrgByID(id); err != nil && !hasCode(err, http.StatusNotFound) {
+		return errors.Wrap(err, "cannot delete Grafana organization")
+	}
+	return nil
+}
+
+func parseOrgID(orgID string) (int64, error) {
+	id, err := strconv.ParseInt(orgID, 10, 64)
+	return id, errors.Wrapf(err, "invalid Grafana organization ID %q", orgID)
+}
+
+// hasCode returns true when err is a Grafana API response with the given HTTP
+// status code. Both the typed responses generated for documented status codes
+// and the generic runtime.APIError implement IsCode.
+func hasCode(err error, code int) bool {
+	var c interface{ IsCode(int) bool }
+	return errors.As(err, &c) && c.IsCode(code)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/grafana/grafana-openapi-client-go/client/orgs"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/pkg/errors"
+)
+
+// mockOrgs implements OrgClient for testing, backed by a map of org ID to name
+// and a map of org ID to the roles of its members by user ID.
+type mockOrgs struct {
+	orgs    map[int64]string
+	members map[int64]map[int64]string
+	logins  map[string]int64
+	nextID  int64
+	err     error
+	created []string
+	renamed map[int64]string
+	deleted []int64
+}
+
+func notFound() error {
+	return runtime.NewAPIError("not found", nil, http.StatusNotFound)
+}
+
+func (m *mockOrgs) CreateOrg(body *models.CreateOrgCommand, _ ...orgs.ClientOption) (*orgs.CreateOrgOK, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.nextID++
+	id := m.nextID
+	m.orgs[id] = body.Name
+	m.created = append(m.created, body.Name)
+	return &orgs.CreateOrgOK{Payload: &models.CreateOrgOKBody{OrgID: &id}}, nil
+}
+
+func (m *mockOrgs) GetOrgByID(id int64, _ ...orgs.ClientOption) (*orgs.GetOrgByIDOK, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	name, ok := m.orgs[id]
+	if !ok {
+		return nil, notFound()
+	}
+	return &orgs.GetOrgByIDOK{Payload: &models.OrgDetailsDTO{ID: id, Name: name}}, nil
+}
+
+func (m *mockOrgs) GetOrgByName(name string, _ ...orgs.ClientOption) (*orgs.GetOrgByNameOK, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for id, n := range m.orgs {
+		if n == name {
+			return &orgs.GetOrgByNameOK{Payload: &models.OrgDetailsDTO{ID: id, Name: n}}, nil
+		}
+	}
+	return nil, notFound()
+}
+
+func (m *mockOrgs) UpdateOrg(id int64, body *models.UpdateOrgForm, _ ...orgs.ClientOption) (*orgs.UpdateOrgOK, error) {
+	m.orgs[id] = body.Name
+	if m.renamed == nil {
+		m.renamed = map[int64]string{}
+	}
+	m.renamed[id] = body.Name
+	return &orgs.UpdateOrgOK{}, nil
+}
+
+func (m *mockOrgs) DeleteOrgByID(id int64, _ ...orgs.ClientOption) (*orgs.DeleteOrgByIDOK, error) {
+	if _, ok := m.orgs[id]; !ok {
+		return nil, &orgs.DeleteOrgByIDNotFound{}
+	}
+	delete(m.orgs, id)
+	m.deleted = append(m.deleted, id)
+	return &orgs.DeleteOrgByIDOK{}, nil
+}
+
+func (m *mockOrgs) GetOrgUsers(orgID int64, _ ...orgs.ClientOption) (*orgs.GetOrgUsersOK, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var payload []*models.OrgUserDTO
+	for id, role := range m.members[orgID] {
+		payload = append(payload, &models.OrgUserDTO{OrgID: orgID, UserID: id, Role: role})
+	}
+	return &orgs.GetOrgUsersOK{Payload: payload}, nil
+}
+
+func (m *mockOrgs) AddOrgUser(orgID int64, body *models.AddOrgUserCommand, _ ...orgs.ClientOption) (*orgs.AddOrgUserOK, error) {
+	if m.members[orgID] == nil {
+		m.members[orgID] = map[int64]string{}
+	}
+	m.members[orgID][m.logins[body.LoginOrEmail]] = body.Role
+	return &orgs.AddOrgUserOK{}, nil
+}
+
+func (m *mockOrgs) UpdateOrgUser(params *orgs.UpdateOrgUserParams, _ ...orgs.ClientOption) (*orgs.UpdateOrgUserOK, error) {
+	m.members[params.OrgID][params.UserID] = params.Body.Role
+	return &orgs.UpdateOrgUserOK{}, nil
+}
+
+func (m *mockOrgs) RemoveOrgUser(userID int64, orgID int64, _ ...orgs.ClientOption) (*orgs.RemoveOrgUserOK, error) {
+	if _, ok := m.members[orgID][userID]; !ok {
+		return nil, notFound()
+	}
+	delete(m.members[orgID], userID)
+	return &orgs.RemoveOrgUserOK{}, nil
+}
+
+func TestEnsureOrg(t *testing.T) {
+	cases := map[string]struct {
+		mock        *mockOrgs
+		orgID       string
+		name        string
+		want        string
+		wantErr     bool
+		wantCreated int
+		wantRenamed bool
+	}{
+		"CreatesMissingOrg": {
+			mock:        &mockOrgs{orgs: map[int64]string{1: "Main Org."}, nextID: 1},
+			name:        "acme",
+			want:        "2",
+			wantCreated: 1,
+		},
+		"AdoptsExistingOrgByName": {
+			mock: &mockOrgs{orgs: map[int64]string{1: "Main Org.", 7: "acme"}, nextID: 7},
+			name: "acme",
+			want: "7",
+		},
+		"KeepsKnownOrg": {
+			mock:  &mockOrgs{orgs: map[int64]string{7: "acme"}, nextID: 7},
+			orgID: "7",
+			name:  "acme",
+			want:  "7",
+		},
+		"RenamesKnownOrg": {
+			mock:        &mockOrgs{orgs: map[int64]string{7: "acme"}, nextID: 7},
+			orgID:       "7",
+			name:        "acme-corp",
+			want:        "7",
+			wantRenamed: true,
+		},
+		"RecreatesDeletedOrg": {
+			mock:        &mockOrgs{orgs: map[int64]string{}, nextID: 7},
+			orgID:       "7",
+			name:        "acme",
+			want:        "8",
+			wantCreated: 1,
+		},
+		"InvalidOrgID": {
+			mock:    &mockOrgs{orgs: map[int64]string{}},
+			orgID:   "org-1",
+			name:    "acme",
+			wantErr: true,
+		},
+		"APIError": {
+			mock:    &mockOrgs{orgs: map[int64]string{}, err: errors.New("connection refused")},
+			name:    "acme",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := EnsureOrg(tc.mock, tc.orgID, tc.name)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("EnsureOrg(...): expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EnsureOrg(...): unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("EnsureOrg(...) = %q, want %q", got, tc.want)
+			}
+			if len(tc.mock.created) != tc.wantCreated {
+				t.Errorf("EnsureOrg(...): created %d orgs, want %d", len(tc.mock.created), tc.wantCreated)
+			}
+			if (len(tc.mock.renamed) > 0) != tc.wantRenamed {
+				t.Errorf("EnsureOrg(...): renamed = %v, want rename %v", tc.mock.renamed, tc.wantRenamed)
+			}
+		})
+	}
+}
+
+func TestOrgExists(t *testing.T) {
+	m := &mockOrgs{orgs: map[int64]string{7: "acme"}}
+	if ok, err := OrgExists(m, "7"); err != nil || !ok {
+		t.Errorf("OrgExists(7) = %v, %v, want true, nil", ok, err)
+	}
+	if ok, err := OrgExists(m, "8"); err != nil || ok {
+		t.Errorf("OrgExists(8) = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestDeleteOrg(t *testing.T) {
+	m := &mockOrgs{orgs: map[int64]string{7: "acme"}}
+	if err := DeleteOrg(m, "7"); err != nil {
+		t.Errorf("DeleteOrg(7): unexpected error: %v", err)
+	}
+	if err := DeleteOrg(m, "7"); err != nil {
+		t.Errorf("DeleteOrg(7) of a missing org: unexpected error: %v", err)
+	}
+	if len(m.deleted) != 1 {
+		t.Errorf("DeleteOrg(...): deleted %v, want exactly one deletion", m.deleted)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Grafana organization roles used in org_mapping entries.
+const (
+	RoleViewer = "Viewer"
+	RoleEditor = "Editor"
+	RoleAdmin  = "Admin"
+)
+
+// OrgMappingEntry is a single <group>:<orgId>:<role> org_mapping entry. Group
+// holds the unescaped group name.
+type OrgMappingEntry struct {
+	Group string
+	OrgID string
+	Role  string
+}
+
+// String renders the entry in org_mapping format, escaping colons in the group.
+func (e OrgMappingEntry) String() string {
+	return fmt.Sprintf("%s:%s:%s", escapeColon(e.Group), e.OrgID, e.Role)
+}
+
+// TenantEntries returns the org_mapping entries expected for a single tenant:
+// its viewer, editor and admin groups, each normalized by NormalizeGroups.
+func TenantEntries(t TenantMapping) []OrgMappingEntry {
+	entries := make([]OrgMappingEntry, 0, len(t.ViewerGroups)+len(t.EditorGroups)+len(t.AdminGroups))
+	for _, g := range NormalizeGroups(t.ViewerGroups) {
+		entries = append(entries, OrgMappingEntry{Group: g, OrgID: t.OrgID, Role: RoleViewer})
+	}
+	for _, g := range NormalizeGroups(t.EditorGroups) {
+		entries = append(entries, OrgMappingEntry{Group: g, OrgID: t.OrgID, Role: RoleEditor})
+	}
+	for _, g := range NormalizeGroups(t.AdminGroups) {
+		entries = append(entries, OrgMappingEntry{Group: g, OrgID: t.OrgID, Role: RoleAdmin})
+	}
+	return entries
+}
+
+// NormalizeGroups returns a sorted copy of groups without surrounding
+// whitespace, empty groups and duplicates, so that the order in which groups
+// are listed never changes the org_mapping.
+func NormalizeGroups(groups []string) []string {
+	if len(groups) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if g = strings.TrimSpace(g); g != "" {
+			out = append(out, g)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// ParseOrgMapping parses a comma-separated org_mapping value. Colons escaped as
+// \: (see escapeColon) are part of the group name. Grafana defaults the role to
+// Viewer when it is omitted; entries without an org ID are skipped.
+func ParseOrgMapping(orgMapping string) []OrgMappingEntry {
+	parts := SplitOrgMapping(orgMapping)
+	entries := make([]OrgMappingEntry, 0, len(parts))
+	for _, p := range parts {
+		fields := splitUnescaped(p)
+		if len(fields) < 2 || fields[1] == "" {
+			continue
+		}
+		e := OrgMappingEntry{Group: fields[0], OrgID: fields[1], Role: RoleViewer}
+		if len(fields) > 2 && fields[2] != "" {
+			e.Role = fields[2]
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// splitUnescaped splits an org_mapping entry on colons that are not escaped
+// with a backslash, unescaping \: in the resulting fields.
+func splitUnescaped(s string) []string {
+	var (
+		fields []string
+		b      strings.Builder
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == ':':
+			b.WriteByte(':')
+			i++
+		case s[i] == ':':
+			fields = append(fields, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return append(fields, b.String())
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseOrgMapping(t *testing.T) {
+	cases := map[string]struct {
+		orgMapping string
+		want       []OrgMappingEntry
+	}{
+		"Empty": {
+			orgMapping: "",
+			want:       []OrgMappingEntry{},
+		},
+		"Simple": {
+			orgMapping: "team-a:1:Viewer, team-b:2:Admin",
+			want: []OrgMappingEntry{
+				{Group: "team-a", OrgID: "1", Role: RoleViewer},
+				{Group: "team-b", OrgID: "2", Role: RoleAdmin},
+			},
+		},
+		"EscapedColons": {
+			orgMapping: `oidc\:team\:editors:org-1:Editor`,
+			want: []OrgMappingEntry{
+				{Group: "oidc:team:editors", OrgID: "org-1", Role: RoleEditor},
+			},
+		},
+		"DefaultRole": {
+			orgMapping: "team-a:1",
+			want: []OrgMappingEntry{
+				{Group: "team-a", OrgID: "1", Role: RoleViewer},
+			},
+		},
+		"Malformed": {
+			orgMapping: "no-org,team-a::Viewer,team-b:3:Editor",
+			want: []OrgMappingEntry{
+				{Group: "team-b", OrgID: "3", Role: RoleEditor},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ParseOrgMapping(tc.orgMapping)); diff != "" {
+				t.Errorf("ParseOrgMapping(%q): -want, +got:\n%s", tc.orgMapping, diff)
+			}
+		})
+	}
+}
+
+func TestOrgMappingRoundTrip(t *testing.T) {
+	tm := TenantMapping{
+		OrgID:        "org-1",
+		ViewerGroups: []string{"oidc:team:viewers", "plain"},
+		EditorGroups: []string{"editors"},
+		AdminGroups:  []string{"ns:admins"},
+	}
+	if diff := cmp.Diff(TenantEntries(tm), ParseOrgMapping(BuildOrgMapping([]TenantMapping{tm}))); diff != "" {
+		t.Errorf("ParseOrgMapping(BuildOrgMapping(...)): -want, +got:\n%s", diff)
+	}
+}
+
+func TestNormalizeGroups(t *testing.T) {
+	cases := map[string]struct {
+		groups []string
+		want   []string
+	}{
+		"Empty": {},
+		"Sorted": {
+			groups: []string{"b", "a"},
+			want:   []string{"a", "b"},
+		},
+		"Trimmed": {
+			groups: []string{" a ", "\tb"},
+			want:   []string{"a", "b"},
+		},
+		"Deduplicated": {
+			groups: []string{"a", "b", " a", "", "  "},
+			want:   []string{"a", "b"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, NormalizeGroups(tc.groups)); diff != "" {
+				t.Errorf("NormalizeGroups(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
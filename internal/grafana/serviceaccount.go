@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	goapi "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/client/service_accounts"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/pkg/errors"
+)
+
+// ServiceAccountClient is the subset of the Grafana service accounts API used
+// by this package.
+type ServiceAccountClient interface {
+	SearchOrgServiceAccountsWithPaging(params *service_accounts.SearchOrgServiceAccountsWithPagingParams, opts ...service_accounts.ClientOption) (*service_accounts.SearchOrgServiceAccountsWithPagingOK, error)
+	CreateServiceAccount(params *service_accounts.CreateServiceAccountParams, opts ...service_accounts.ClientOption) (*service_accounts.CreateServiceAccountCreated, error)
+	UpdateServiceAccount(params *service_accounts.UpdateServiceAccountParams, opts ...service_accounts.ClientOption) (*service_accounts.UpdateServiceAccountOK, error)
+	DeleteServiceAccount(serviceAccountID int64, opts ...service_accounts.ClientOption) (*service_accounts.DeleteServiceAccountOK, error)
+	CreateToken(params *service_accounts.CreateTokenParams, opts ...service_accounts.ClientOption) (*service_accounts.CreateTokenOK, error)
+	ListTokens(serviceAccountID int64, opts ...service_accounts.ClientOption) (*service_accounts.ListTokensOK, error)
+	DeleteToken(tokenID int64, serviceAccountID int64, opts ...service_accounts.ClientOption) (*service_accounts.DeleteTokenOK, error)
+}
+
+// EnsureServiceAccount makes sure a service account with the given name and
+// role exists in the organization with the given ID and returns its ID. An
+// existing service account with the same name is adopted and its role
+// updated when necessary.
+func EnsureServiceAccount(sc ServiceAccountClient, orgID, name, role string) (int64, error) {
+	id, err := parseOrgID(orgID)
+	if err != nil {
+		return 0, err
+	}
+
+	params := service_accounts.NewSearchOrgServiceAccountsWithPagingParams().WithQuery(&name)
+	found, err := sc.SearchOrgServiceAccountsWithPaging(params, inOrg(id))
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot search Grafana service accounts")
+	}
+	for _, sa := range found.Payload.ServiceAccounts {
+		if sa == nil || sa.Name != name {
+			continue
+		}
+		if sa.Role != role {
+			update := service_accounts.NewUpdateServiceAccountParams().
+				WithServiceAccountID(sa.ID).
+				WithBody(&models.UpdateServiceAccountForm{Role: role})
+			if _, err := sc.UpdateServiceAccount(update, inOrg(id)); err != nil {
+				return 0, errors.Wrapf(err, "cannot update Grafana service account %s", name)
+			}
+		}
+		return sa.ID, nil
+	}
+
+	create := service_accounts.NewCreateServiceAccountParams().
+		WithBody(&models.CreateServiceAccountForm{Name: name, Role: role})
+	created, err := sc.CreateServiceAccount(create, inOrg(id))
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot create Grafana service account %s", name)
+	}
+	if created.Payload == nil || created.Payload.ID == 0 {
+		return 0, errors.New("Grafana did not return the ID of the created service account")
+	}
+	return created.Payload.ID, nil
+}
+
+// CreateServiceAccountToken creates a token with the given name for a service
+// account of the organization with the given ID and returns its key. The
+// token expires after ttl, or never if ttl is zero. Grafana returns the key
+// only once, so callers must store it.
+func CreateServiceAccountToken(sc ServiceAccountClient, orgID string, serviceAccountID int64, name string, ttl time.Duration) (string, error) {
+	id, err := parseOrgID(orgID)
+	if err != nil {
+		return "", err
+	}
+	params := service_accounts.NewCreateTokenParams().
+		WithServiceAccountID(serviceAccountID).
+		WithBody(&models.AddServiceAccountTokenCommand{Name: name, SecondsToLive: int64(ttl.Seconds())})
+	resp, err := sc.CreateToken(params, inOrg(id))
+	if err != nil {
+		return "", errors.Wrap(err, "cannot create Grafana service account token")
+	}
+	if resp.Payload == nil || resp.Payload.Key == "" {
+		return "", errors.New("Grafana did not return the key of the created token")
+	}
+	return resp.Payload.Key, nil
+}
+
+// DeleteExpiredTokens deletes the expired tokens of a service account of the
+// organization with the given ID, which Grafana keeps until deleted.
+func DeleteExpiredTokens(sc ServiceAccountClient, orgID string, serviceAccountID int64) error {
+	id, err := parseOrgID(orgID)
+	if err != nil {
+		return err
+	}
+	resp, err := sc.ListTokens(serviceAccountID, inOrg(id))
+	if err != nil {
+		return errors.Wrap(err, "cannot list Grafana service account tokens")
+	}
+	for _, t := range resp.Payload {
+		if t == nil || !t.HasExpired {
+			continue
+		}
+		if _, err := sc.DeleteToken(t.ID, serviceAccountID, inOrg(id)); err != nil && !hasCode(err, http.StatusNotFound) {
+			return errors.Wrapf(err, "cannot delete Grafana service account token %s", t.Name)
+		}
+	}
+	return nil
+}
+
+// DeleteServiceAccount deletes a service account of the organization with
+// the given ID, along with its tokens. Deleting a service account that does
+// not exist is not an error.
+func DeleteServiceAccount(sc ServiceAccountClient, orgID string, serviceAccountID int64) error {
+	id, err := parseOrgID(orgID)
+	if err != nil {
+		return err
+	}
+	if _, err := sc.DeleteServiceAccount(serviceAccountID, inOrg(id)); err != nil && !hasCode(err, http.StatusNotFound) {
+		return errors.Wrap(err, "cannot delete Grafana service account")
+	}
+	return nil
+}
+
+// inOrg scopes a single service accounts API call to the organization with
+// the given ID. Unlike GrafanaHTTPAPI.WithOrgID it keeps the transport, and
+// therefore the TLS and retry settings, of the client.
+func inOrg(orgID int64) service_accounts.ClientOption {
+	return func(op *runtime.ClientOperation) {
+		op.Params = orgParams{ClientRequestWriter: op.Params, orgID: orgID}
+	}
+}
+
+// orgParams adds the organization header to the parameters of a request.
+type orgParams struct {
+	runtime.ClientRequestWriter
+	orgID int64
+}
+
+func (p orgParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+	if err := r.SetHeaderParam(goapi.OrgIDHeader, strconv.FormatInt(p.orgID, 10)); err != nil {
+		return err
+	}
+	return p.ClientRequestWriter.WriteToRequest(r, reg)
+}
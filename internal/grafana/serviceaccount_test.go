@@ -0,0 +1,218 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/google/go-cmp/cmp"
+	goapi "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/client/service_accounts"
+	"github.com/grafana/grafana-openapi-client-go/models"
+)
+
+// mockServiceAccounts implements ServiceAccountClient for testing, backed by
+// the service accounts of each organization. Calls are attributed to the
+// organization named by their org header.
+type mockServiceAccounts struct {
+	accounts      map[int64][]*models.ServiceAccountDTO
+	nextID        int64
+	tokens        map[int64][]string
+	ttls          []int64
+	listed        []*models.TokenDTO
+	deleted       []int64
+	deletedTokens []int64
+}
+
+// headerRequest records the headers set on a request.
+type headerRequest struct {
+	runtime.ClientRequest
+	headers map[string]string
+}
+
+func (r *headerRequest) SetHeaderParam(name string, values ...string) error {
+	r.headers[name] = values[0]
+	return nil
+}
+
+// orgOf returns the organization a call with the given options is scoped to.
+func orgOf(opts []service_accounts.ClientOption) int64 {
+	op := &runtime.ClientOperation{Params: runtime.ClientRequestWriterFunc(func(runtime.ClientRequest, strfmt.Registry) error { return nil })}
+	for _, o := range opts {
+		o(op)
+	}
+	r := &headerRequest{headers: map[string]string{}}
+	_ = op.Params.WriteToRequest(r, strfmt.Default)
+	id, _ := strconv.ParseInt(r.headers[goapi.OrgIDHeader], 10, 64)
+	return id
+}
+
+func (m *mockServiceAccounts) SearchOrgServiceAccountsWithPaging(params *service_accounts.SearchOrgServiceAccountsWithPagingParams, opts ...service_accounts.ClientOption) (*service_accounts.SearchOrgServiceAccountsWithPagingOK, error) {
+	return &service_accounts.SearchOrgServiceAccountsWithPagingOK{Payload: &models.SearchOrgServiceAccountsResult{ServiceAccounts: m.accounts[orgOf(opts)]}}, nil
+}
+
+func (m *mockServiceAccounts) CreateServiceAccount(params *service_accounts.CreateServiceAccountParams, opts ...service_accounts.ClientOption) (*service_accounts.CreateServiceAccountCreated, error) {
+	m.nextID++
+	sa := &models.ServiceAccountDTO{ID: m.nextID, Name: params.Body.Name, Role: params.Body.Role, OrgID: orgOf(opts)}
+	m.accounts[sa.OrgID] = append(m.accounts[sa.OrgID], sa)
+	return &service_accounts.CreateServiceAccountCreated{Payload: sa}, nil
+}
+
+func (m *mockServiceAccounts) UpdateServiceAccount(params *service_accounts.UpdateServiceAccountParams, opts ...service_accounts.ClientOption) (*service_accounts.UpdateServiceAccountOK, error) {
+	for _, sa := range m.accounts[orgOf(opts)] {
+		if sa.ID == params.ServiceAccountID {
+			sa.Role = params.Body.Role
+		}
+	}
+	return &service_accounts.UpdateServiceAccountOK{}, nil
+}
+
+func (m *mockServiceAccounts) DeleteServiceAccount(serviceAccountID int64, opts ...service_accounts.ClientOption) (*service_accounts.DeleteServiceAccountOK, error) {
+	org := orgOf(opts)
+	for i, sa := range m.accounts[org] {
+		if sa.ID == serviceAccountID {
+			m.accounts[org] = append(m.accounts[org][:i], m.accounts[org][i+1:]...)
+			m.deleted = append(m.deleted, serviceAccountID)
+			return &service_accounts.DeleteServiceAccountOK{}, nil
+		}
+	}
+	return nil, notFound()
+}
+
+func (m *mockServiceAccounts) CreateToken(params *service_accounts.CreateTokenParams, _ ...service_accounts.ClientOption) (*service_accounts.CreateTokenOK, error) {
+	if m.tokens == nil {
+		m.tokens = map[int64][]string{}
+	}
+	m.tokens[params.ServiceAccountID] = append(m.tokens[params.ServiceAccountID], params.Body.Name)
+	m.ttls = append(m.ttls, params.Body.SecondsToLive)
+	return &service_accounts.CreateTokenOK{Payload: &models.NewAPIKeyResult{Name: params.Body.Name, Key: "glsa_" + params.Body.Name}}, nil
+}
+
+func (m *mockServiceAccounts) ListTokens(_ int64, _ ...service_accounts.ClientOption) (*service_accounts.ListTokensOK, error) {
+	return &service_accounts.ListTokensOK{Payload: m.listed}, nil
+}
+
+func (m *mockServiceAccounts) DeleteToken(tokenID, _ int64, _ ...service_accounts.ClientOption) (*service_accounts.DeleteTokenOK, error) {
+	m.deletedTokens = append(m.deletedTokens, tokenID)
+	return &service_accounts.DeleteTokenOK{}, nil
+}
+
+func TestEnsureServiceAccount(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		accounts map[int64][]*models.ServiceAccountDTO
+		orgID    string
+		name     string
+		role     string
+		want     int64
+		wantErr  bool
+		wantSAs  map[int64][]*models.ServiceAccountDTO
+	}{
+		"CreatesMissing": {
+			reason:   "Should create a missing service account in the given organization.",
+			accounts: map[int64][]*models.ServiceAccountDTO{1: {{ID: 5, Name: "other", Role: RoleAdmin}}},
+			orgID:    "2",
+			name:     "acme",
+			role:     RoleViewer,
+			want:     6,
+			wantSAs: map[int64][]*models.ServiceAccountDTO{
+				1: {{ID: 5, Name: "other", Role: RoleAdmin}},
+				2: {{ID: 6, Name: "acme", Role: RoleViewer, OrgID: 2}},
+			},
+		},
+		"AdoptsExisting": {
+			reason:   "Should adopt a service account with the same name and update its role.",
+			accounts: map[int64][]*models.ServiceAccountDTO{2: {{ID: 3, Name: "acme-ci"}, {ID: 4, Name: "acme", Role: RoleViewer}}},
+			orgID:    "2",
+			name:     "acme",
+			role:     RoleEditor,
+			want:     4,
+			wantSAs:  map[int64][]*models.ServiceAccountDTO{2: {{ID: 3, Name: "acme-ci"}, {ID: 4, Name: "acme", Role: RoleEditor}}},
+		},
+		"InvalidOrgID": {
+			reason:   "Should reject an organization ID that is not a number.",
+			accounts: map[int64][]*models.ServiceAccountDTO{},
+			orgID:    "org-1",
+			wantErr:  true,
+			wantSAs:  map[int64][]*models.ServiceAccountDTO{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mockServiceAccounts{accounts: tc.accounts, nextID: 5}
+			got, err := EnsureServiceAccount(m, tc.orgID, tc.name, tc.role)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\nEnsureServiceAccount(...): unexpected error: %v", tc.reason, err)
+			}
+			if got != tc.want {
+				t.Errorf("\n%s\nEnsureServiceAccount(...): got %d, want %d", tc.reason, got, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantSAs, m.accounts); diff != "" {
+				t.Errorf("\n%s\nEnsureServiceAccount(...): -want accounts, +got accounts:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateServiceAccountToken(t *testing.T) {
+	m := &mockServiceAccounts{}
+	key, err := CreateServiceAccountToken(m, "2", 4, "acme-1", time.Hour)
+	if err != nil {
+		t.Fatalf("CreateServiceAccountToken(...): unexpected error: %v", err)
+	}
+	if key != "glsa_acme-1" {
+		t.Errorf("CreateServiceAccountToken(...): got key %q, want %q", key, "glsa_acme-1")
+	}
+	if diff := cmp.Diff(map[int64][]string{4: {"acme-1"}}, m.tokens); diff != "" {
+		t.Errorf("CreateServiceAccountToken(...): -want tokens, +got tokens:\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{3600}, m.ttls); diff != "" {
+		t.Errorf("CreateServiceAccountToken(...): -want secondsToLive, +got secondsToLive:\n%s", diff)
+	}
+}
+
+func TestDeleteExpiredTokens(t *testing.T) {
+	m := &mockServiceAccounts{listed: []*models.TokenDTO{
+		{ID: 1, Name: "acme-1", HasExpired: true},
+		{ID: 2, Name: "acme-2"},
+		{ID: 3, Name: "manual", HasExpired: true},
+	}}
+	if err := DeleteExpiredTokens(m, "2", 4); err != nil {
+		t.Fatalf("DeleteExpiredTokens(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int64{1, 3}, m.deletedTokens); diff != "" {
+		t.Errorf("DeleteExpiredTokens(...): -want deleted, +got deleted:\n%s", diff)
+	}
+}
+
+func TestDeleteServiceAccount(t *testing.T) {
+	m := &mockServiceAccounts{accounts: map[int64][]*models.ServiceAccountDTO{2: {{ID: 4, Name: "acme"}}}}
+	if err := DeleteServiceAccount(m, "2", 4); err != nil {
+		t.Fatalf("DeleteServiceAccount(...): unexpected error: %v", err)
+	}
+	if err := DeleteServiceAccount(m, "2", 4); err != nil {
+		t.Errorf("DeleteServiceAccount(...): deleting a missing service account: unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int64{4}, m.deleted); diff != "" {
+		t.Errorf("DeleteServiceAccount(...): -want deleted, +got deleted:\n%s", diff)
+	}
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package grafana
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/grafana/grafana-openapi-client-go/client/sso_settings"
@@ -26,10 +29,14 @@ import (
 	"github.com/pkg/errors"
 )
 
-const ssoProvider = "generic_oauth"
+// DefaultSSOProvider is the SSO provider org_mapping is written to unless
+// another one is selected with WithProvider.
+const DefaultSSOProvider = "generic_oauth"
 
 // TenantMapping holds the fields needed to produce org_mapping entries for a tenant.
+// TenantID orders the entries of tenants in the rendered org_mapping.
 type TenantMapping struct {
+	TenantID     string
 	OrgID        string
 	ViewerGroups []string
 	EditorGroups []string
@@ -42,42 +49,150 @@ type SSOClient interface {
 	UpdateProviderSettings(key string, body *models.UpdateProviderSettingsParamsBody, opts ...sso_settings.ClientOption) (*sso_settings.UpdateProviderSettingsNoContent, error)
 }
 
-// SyncOrgMapping reads the current SSO settings for generic_oauth, computes the
-// org_mapping from all tenants, and writes the updated settings back.
-func SyncOrgMapping(_ context.Context, ssoc SSOClient, tenants []TenantMapping) error {
-	settings, err := getOrInitSettings(ssoc)
+// SyncOption configures SyncOrgMapping.
+type SyncOption func(*syncOptions)
+
+type syncOptions struct {
+	provider string
+	owned    bool
+	previous []string
+	settings map[string]any
+}
+
+// WithProvider makes SyncOrgMapping write the org_mapping of the given SSO
+// provider (e.g. azuread or okta) instead of DefaultSSOProvider.
+func WithProvider(provider string) SyncOption {
+	return func(o *syncOptions) {
+		o.provider = provider
+	}
+}
+
+// WithOwnedEntries makes SyncOrgMapping only add and remove the entries it
+// generated. Entries in previous were written by an earlier sync and may be
+// removed; any other entry already present in Grafana is preserved.
+func WithOwnedEntries(previous []string) SyncOption {
+	return func(o *syncOptions) {
+		o.owned = true
+		o.previous = previous
+	}
+}
+
+// WithSettings makes SyncOrgMapping write the given SSO settings, keyed as in
+// the Grafana API (e.g. roleAttributePath), together with the org_mapping.
+// Settings that are not given are preserved.
+func WithSettings(settings map[string]any) SyncOption {
+	return func(o *syncOptions) {
+		o.settings = settings
+	}
+}
+
+// HasOrgAttributePath reports whether SSO settings have a non-empty
+// orgAttributePath. Grafana ignores org_mapping unless orgAttributePath
+// selects the claim carrying the groups.
+func HasOrgAttributePath(settings map[string]any) bool {
+	path, _ := settings["orgAttributePath"].(string)
+	return strings.TrimSpace(path) != ""
+}
+
+// SyncOrgMapping reads the current SSO settings of a provider, computes the
+// org_mapping from all tenants, and writes the updated settings back unless the
+// provider holds the same entries, in any order, and settings already. By
+// default the org_mapping is replaced wholesale; see WithOwnedEntries. It
+// returns the entries generated from the tenants, which callers should pass
+// back through WithOwnedEntries on the next sync. The SSO settings API exposes
+// no version or ETag to make the write conditional on, so a change made to the
+// provider's settings between the read and the write is overwritten.
+func SyncOrgMapping(_ context.Context, ssoc SSOClient, tenants []TenantMapping, opts ...SyncOption) ([]string, error) {
+	o := &syncOptions{provider: DefaultSSOProvider}
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	settings, err := getOrInitSettings(ssoc, o.provider)
 	if err != nil {
-		return errors.Wrap(err, "cannot get SSO settings")
+		return nil, errors.Wrap(err, "cannot get SSO settings")
 	}
 
-	settings["orgMapping"] = BuildOrgMapping(tenants)
+	desired := BuildOrgMappingEntries(tenants)
+	entries := desired
+	current, _ := settings["orgMapping"].(string)
+	if o.owned {
+		entries = MergeOrgMappingEntries(SplitOrgMapping(current), o.previous, desired)
+	}
+	unchanged := sameEntries(SplitOrgMapping(current), entries) && !SettingsDrifted(settings, o.settings)
+	settings["orgMapping"] = strings.Join(entries, ",")
+	maps.Copy(settings, o.settings)
+	if unchanged {
+		return desired, nil
+	}
 
 	body := &models.UpdateProviderSettingsParamsBody{
-		Provider: ssoProvider,
+		Provider: o.provider,
 		Settings: settings,
 	}
-	if _, err := ssoc.UpdateProviderSettings(ssoProvider, body); err != nil {
-		return errors.Wrap(err, "cannot update SSO settings")
+	if _, err := ssoc.UpdateProviderSettings(o.provider, body); err != nil {
+		return nil, errors.Wrap(err, "cannot update SSO settings")
 	}
-	return nil
+	return desired, nil
 }
 
-// OrgMappingContains checks whether the given org_mapping string contains any
-// entry for the specified orgId. Entries have the format <group>:<orgId>:<role>.
-func OrgMappingContains(orgMapping, orgID string) bool {
-	if orgMapping == "" {
-		return false
-	}
-	// Check if any entry has this orgId as the second field (format: group:orgId:role)
-	suffix := ":" + orgID + ":"
-	for _, part := range strings.Split(orgMapping, ",") {
-		if strings.Contains(strings.TrimSpace(part), suffix) {
+// SettingsDrifted reports whether any of the desired SSO settings differs from
+// the current settings of a provider. Values are compared by their string
+// form, since Grafana may return booleans and numbers as strings.
+func SettingsDrifted(current, desired map[string]any) bool {
+	for k, want := range desired {
+		got, ok := current[k]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
 			return true
 		}
 	}
 	return false
 }
 
+// sameEntries reports whether a and b hold the same org_mapping entries,
+// regardless of their order.
+func sameEntries(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// MergeOrgMappingEntries combines the entries currently held by Grafana with the
+// desired entries. Entries in current that are neither desired nor listed in
+// previous are foreign (e.g. configured by hand) and are kept in their original
+// order, followed by the desired entries.
+func MergeOrgMappingEntries(current, previous, desired []string) []string {
+	owned := make(map[string]bool, len(previous)+len(desired))
+	for _, e := range previous {
+		owned[e] = true
+	}
+	for _, e := range desired {
+		owned[e] = true
+	}
+
+	merged := make([]string, 0, len(current)+len(desired))
+	for _, e := range current {
+		if !owned[e] {
+			merged = append(merged, e)
+		}
+	}
+	return append(merged, desired...)
+}
+
+// SplitOrgMapping splits a comma-separated org_mapping value into its entries,
+// dropping surrounding whitespace and empty entries.
+func SplitOrgMapping(orgMapping string) []string {
+	parts := strings.Split(orgMapping, ",")
+	entries := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			entries = append(entries, p)
+		}
+	}
+	return entries
+}
+
 // BuildOrgMapping produces the comma-separated org_mapping value from a set of
 // tenant mappings. For each tenant it emits:
 //   - <group>:<orgId>:Viewer  for each ViewerGroup
@@ -86,19 +201,22 @@ func OrgMappingContains(orgMapping, orgID string) bool {
 // Group names containing colons are automatically escaped with \: to prevent
 // parsing issues in Grafana's org_mapping format.
 func BuildOrgMapping(tenants []TenantMapping) string {
+	return strings.Join(BuildOrgMappingEntries(tenants), ",")
+}
+
+// BuildOrgMappingEntries returns the individual org_mapping entries for a set of
+// tenant mappings, in the order BuildOrgMapping joins them: tenants sorted by
+// TenantID, and the groups of each tenant normalized.
+func BuildOrgMappingEntries(tenants []TenantMapping) []string {
+	tenants = slices.Clone(tenants)
+	slices.SortStableFunc(tenants, func(a, b TenantMapping) int { return cmp.Compare(a.TenantID, b.TenantID) })
 	entries := make([]string, 0, len(tenants))
 	for _, t := range tenants {
-		for _, g := range t.ViewerGroups {
-			entries = append(entries, fmt.Sprintf("%s:%s:Viewer", escapeColon(g), t.OrgID))
-		}
-		for _, g := range t.EditorGroups {
-			entries = append(entries, fmt.Sprintf("%s:%s:Editor", escapeColon(g), t.OrgID))
-		}
-		for _, g := range t.AdminGroups {
-			entries = append(entries, fmt.Sprintf("%s:%s:Admin", escapeColon(g), t.OrgID))
+		for _, e := range TenantEntries(t) {
+			entries = append(entries, e.String())
 		}
 	}
-	return strings.Join(entries, ",")
+	return entries
 }
 
 // escapeColon escapes colons in a string for use in Grafana org_mapping.
@@ -108,10 +226,10 @@ func escapeColon(s string) string {
 	return strings.ReplaceAll(s, ":", `\:`)
 }
 
-// getOrInitSettings fetches the current SSO settings for generic_oauth.
-// If the provider returns 404, an empty settings map is returned.
-func getOrInitSettings(ssoc SSOClient) (map[string]interface{}, error) {
-	resp, err := ssoc.GetProviderSettings(ssoProvider)
+// getOrInitSettings fetches the current settings of an SSO provider. If the
+// provider returns 404, an empty settings map is returned.
+func getOrInitSettings(ssoc SSOClient, provider string) (map[string]interface{}, error) {
+	resp, err := ssoc.GetProviderSettings(provider)
 	if err != nil {
 		// If the provider is not configured yet, start with an empty map.
 		if IsNotFound(err) {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/grafana/grafana-openapi-client-go/client/sso_settings"
 	"github.com/grafana/grafana-openapi-client-go/models"
 	"github.com/pkg/errors"
@@ -29,6 +30,7 @@ import (
 type mockSSO struct {
 	getResp *sso_settings.GetProviderSettingsOK
 	getErr  error
+	putKey  string
 	putBody *models.UpdateProviderSettingsParamsBody
 	putErr  error
 }
@@ -38,6 +40,7 @@ func (m *mockSSO) GetProviderSettings(key string, _ ...sso_settings.ClientOption
 }
 
 func (m *mockSSO) UpdateProviderSettings(key string, body *models.UpdateProviderSettingsParamsBody, _ ...sso_settings.ClientOption) (*sso_settings.UpdateProviderSettingsNoContent, error) {
+	m.putKey = key
 	m.putBody = body
 	if m.putErr != nil {
 		return nil, m.putErr
@@ -97,7 +100,16 @@ func TestBuildOrgMapping(t *testing.T) {
 			tenants: []TenantMapping{
 				{OrgID: "org-1", ViewerGroups: []string{"simple-group", "ns:complex:group"}, EditorGroups: []string{"editors"}},
 			},
-			want: `simple-group:org-1:Viewer,ns\:complex\:group:org-1:Viewer,editors:org-1:Editor`,
+			want: `ns\:complex\:group:org-1:Viewer,simple-group:org-1:Viewer,editors:org-1:Editor`,
+		},
+		"Normalized": {
+			// Tenants are sorted by tenantId, groups are trimmed, deduplicated
+			// and sorted.
+			tenants: []TenantMapping{
+				{TenantID: "beta", OrgID: "org-2", ViewerGroups: []string{"b-viewers"}},
+				{TenantID: "alpha", OrgID: "org-1", ViewerGroups: []string{" team-b", "team-a", "team-b", ""}},
+			},
+			want: "team-a:org-1:Viewer,team-b:org-1:Viewer,b-viewers:org-2:Viewer",
 		},
 		"WithAdminGroups": {
 			tenants: []TenantMapping{
@@ -123,44 +135,99 @@ func TestBuildOrgMapping(t *testing.T) {
 	}
 }
 
-func TestOrgMappingContains(t *testing.T) {
+func TestSyncOrgMappingWithProvider(t *testing.T) {
+	mock := &mockSSO{getErr: &sso_settings.GetProviderSettingsNotFound{}}
+	tenants := []TenantMapping{{OrgID: "org-1", ViewerGroups: []string{"viewers"}}}
+
+	if _, err := SyncOrgMapping(context.Background(), mock, tenants, WithProvider("azuread")); err != nil {
+		t.Fatalf("SyncOrgMapping(...): unexpected error: %v", err)
+	}
+	if mock.putKey != "azuread" || mock.putBody.Provider != "azuread" {
+		t.Errorf("SyncOrgMapping(...): updated provider %q (body %q), want %q", mock.putKey, mock.putBody.Provider, "azuread")
+	}
+}
+
+func TestSyncOrgMappingWithSettings(t *testing.T) {
+	mock := &mockSSO{getResp: &sso_settings.GetProviderSettingsOK{Payload: &models.GetProviderSettingsOKBody{
+		Settings: map[string]any{"clientId": "my-client", "roleAttributePath": "'Viewer'"},
+	}}}
+	tenants := []TenantMapping{{OrgID: "org-1", ViewerGroups: []string{"viewers"}}}
+
+	_, err := SyncOrgMapping(context.Background(), mock, tenants, WithSettings(map[string]any{
+		"roleAttributePath": "'Editor'",
+		"skipOrgRoleSync":   true,
+	}))
+	if err != nil {
+		t.Fatalf("SyncOrgMapping(...): unexpected error: %v", err)
+	}
+	want := map[string]any{
+		"clientId":          "my-client",
+		"orgMapping":        "viewers:org-1:Viewer",
+		"roleAttributePath": "'Editor'",
+		"skipOrgRoleSync":   true,
+	}
+	if diff := cmp.Diff(want, mock.putBody.Settings); diff != "" {
+		t.Errorf("SyncOrgMapping(...): -want settings, +got settings:\n%s", diff)
+	}
+}
+
+func TestHasOrgAttributePath(t *testing.T) {
 	cases := map[string]struct {
-		orgMapping string
-		orgID      string
-		want       bool
+		settings map[string]any
+		want     bool
 	}{
-		"Present": {
-			orgMapping: "team-a:org-1:Viewer,team-b:org-2:Viewer",
-			orgID:      "org-1",
-			want:       true,
+		"Missing": {
+			settings: map[string]any{},
 		},
-		"Absent": {
-			orgMapping: "team-a:org-1:Viewer,team-b:org-2:Viewer",
-			orgID:      "org-3",
-			want:       false,
+		"Blank": {
+			settings: map[string]any{"orgAttributePath": " "},
 		},
-		"Empty": {
-			orgMapping: "",
-			orgID:      "org-1",
-			want:       false,
+		"Configured": {
+			settings: map[string]any{"orgAttributePath": "groups"},
+			want:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := HasOrgAttributePath(tc.settings); got != tc.want {
+				t.Errorf("HasOrgAttributePath(...): got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSettingsDrifted(t *testing.T) {
+	cases := map[string]struct {
+		current map[string]any
+		desired map[string]any
+		want    bool
+	}{
+		"NoDesiredSettings": {
+			current: map[string]any{"roleAttributePath": "'Viewer'"},
+			want:    false,
 		},
-		"SingleMatch": {
-			orgMapping: "viewers:org-1:Viewer",
-			orgID:      "org-1",
-			want:       true,
+		"Equal": {
+			current: map[string]any{"roleAttributePath": "'Viewer'", "skipOrgRoleSync": "true"},
+			desired: map[string]any{"roleAttributePath": "'Viewer'", "skipOrgRoleSync": true},
+			want:    false,
 		},
-		"MultipleEntriesForSameOrg": {
-			orgMapping: "team-a:org-1:Viewer,team-b:org-1:Editor",
-			orgID:      "org-1",
-			want:       true,
+		"Changed": {
+			current: map[string]any{"roleAttributePath": "'Viewer'"},
+			desired: map[string]any{"roleAttributePath": "'Admin'"},
+			want:    true,
+		},
+		"Missing": {
+			current: map[string]any{},
+			desired: map[string]any{"allowAssignGrafanaAdmin": false},
+			want:    true,
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := OrgMappingContains(tc.orgMapping, tc.orgID)
-			if got != tc.want {
-				t.Errorf("OrgMappingContains(%q, %q) = %v, want %v", tc.orgMapping, tc.orgID, got, tc.want)
+			if got := SettingsDrifted(tc.current, tc.desired); got != tc.want {
+				t.Errorf("SettingsDrifted(...) = %v, want %v", got, tc.want)
 			}
 		})
 	}
@@ -168,11 +235,27 @@ func TestOrgMappingContains(t *testing.T) {
 
 func TestSyncOrgMapping(t *testing.T) {
 	cases := map[string]struct {
-		mock    *mockSSO
-		tenants []TenantMapping
-		wantErr bool
-		wantMap string
+		mock        *mockSSO
+		tenants     []TenantMapping
+		wantErr     bool
+		wantMap     string
+		wantNoWrite bool
 	}{
+		"Unchanged": {
+			// Grafana holds the same entries in another order.
+			mock: &mockSSO{
+				getResp: &sso_settings.GetProviderSettingsOK{
+					Payload: &models.GetProviderSettingsOKBody{
+						Settings: map[string]any{"orgMapping": "team-b:org-2:Viewer, team-a:org-1:Viewer"},
+					},
+				},
+			},
+			tenants: []TenantMapping{
+				{TenantID: "a", OrgID: "org-1", ViewerGroups: []string{"team-a"}},
+				{TenantID: "b", OrgID: "org-2", ViewerGroups: []string{"team-b"}},
+			},
+			wantNoWrite: true,
+		},
 		"Success": {
 			mock: &mockSSO{
 				getResp: &sso_settings.GetProviderSettingsOK{
@@ -234,7 +317,7 @@ func TestSyncOrgMapping(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			err := SyncOrgMapping(context.Background(), tc.mock, tc.tenants)
+			_, err := SyncOrgMapping(context.Background(), tc.mock, tc.tenants)
 			if tc.wantErr {
 				if err == nil {
 					t.Error("SyncOrgMapping(...): expected error, got nil")
@@ -246,6 +329,12 @@ func TestSyncOrgMapping(t *testing.T) {
 				return
 			}
 
+			if tc.wantNoWrite {
+				if tc.mock.putBody != nil {
+					t.Error("SyncOrgMapping(...): expected UpdateProviderSettings not to be called")
+				}
+				return
+			}
 			if tc.mock.putBody == nil {
 				t.Fatal("SyncOrgMapping(...): expected UpdateProviderSettings to be called")
 			}
@@ -272,3 +361,87 @@ func TestSyncOrgMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncOrgMappingOwned(t *testing.T) {
+	cases := map[string]struct {
+		current     string
+		previous    []string
+		tenants     []TenantMapping
+		wantMap     string
+		wantManaged []string
+	}{
+		"PreservesForeignEntries": {
+			current:     "break-glass:1:Admin,team-a:org-1:Viewer",
+			previous:    []string{"team-a:org-1:Viewer"},
+			tenants:     []TenantMapping{{OrgID: "org-1", ViewerGroups: []string{"team-a"}}},
+			wantMap:     "break-glass:1:Admin,team-a:org-1:Viewer",
+			wantManaged: []string{"team-a:org-1:Viewer"},
+		},
+		"RemovesPreviouslyManagedEntries": {
+			current:     "team-old:org-1:Viewer,platform:1:Admin",
+			previous:    []string{"team-old:org-1:Viewer"},
+			tenants:     []TenantMapping{{OrgID: "org-1", EditorGroups: []string{"team-new"}}},
+			wantMap:     "platform:1:Admin,team-new:org-1:Editor",
+			wantManaged: []string{"team-new:org-1:Editor"},
+		},
+		"AdoptsMatchingForeignEntries": {
+			current:     "team-a:org-1:Viewer",
+			tenants:     []TenantMapping{{OrgID: "org-1", ViewerGroups: []string{"team-a"}}},
+			wantMap:     "team-a:org-1:Viewer",
+			wantManaged: []string{"team-a:org-1:Viewer"},
+		},
+		"NoTenants": {
+			current:     "platform:1:Admin, team-a:org-1:Viewer",
+			previous:    []string{"team-a:org-1:Viewer"},
+			wantMap:     "platform:1:Admin",
+			wantManaged: []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mock := &mockSSO{
+				getResp: &sso_settings.GetProviderSettingsOK{
+					Payload: &models.GetProviderSettingsOKBody{
+						Settings: map[string]any{"orgMapping": tc.current},
+					},
+				},
+			}
+			managed, err := SyncOrgMapping(context.Background(), mock, tc.tenants, WithOwnedEntries(tc.previous))
+			if err != nil {
+				t.Fatalf("SyncOrgMapping(...): unexpected error: %v", err)
+			}
+			// Grafana keeps its org_mapping when nothing changed.
+			got := tc.current
+			if mock.putBody != nil {
+				settings, _ := mock.putBody.Settings.(map[string]any)
+				got, _ = settings["orgMapping"].(string)
+			}
+			if got != tc.wantMap {
+				t.Errorf("SyncOrgMapping(...): orgMapping = %q, want %q", got, tc.wantMap)
+			}
+			if diff := cmp.Diff(tc.wantManaged, managed); diff != "" {
+				t.Errorf("SyncOrgMapping(...): -want managed, +got managed:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitOrgMapping(t *testing.T) {
+	cases := map[string]struct {
+		orgMapping string
+		want       []string
+	}{
+		"Empty":      {orgMapping: "", want: []string{}},
+		"Single":     {orgMapping: "a:1:Viewer", want: []string{"a:1:Viewer"}},
+		"Whitespace": {orgMapping: " a:1:Viewer , b:2:Editor ,", want: []string{"a:1:Viewer", "b:2:Editor"}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, SplitOrgMapping(tc.orgMapping)); diff != "" {
+				t.Errorf("SplitOrgMapping(%q): -want, +got:\n%s", tc.orgMapping, diff)
+			}
+		})
+	}
+}
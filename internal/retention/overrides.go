@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retention renders tenant retention policies as the per-tenant
+// runtime overrides enforced by Loki, Mimir, Tempo and Pyroscope.
+package retention
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// Keys of the overrides file rendered for each backend.
+const (
+	KeyLoki      = "loki.yaml"
+	KeyMimir     = "mimir.yaml"
+	KeyTempo     = "tempo.yaml"
+	KeyPyroscope = "pyroscope.yaml"
+)
+
+// TenantRetention is the retention input for a single tenant. Durations use
+// the Tenant API format: an integer followed by one of h, d, w, m (months of
+// 30 days) or y. Empty durations leave the backend default in place.
+type TenantRetention struct {
+	TenantID string
+	Logs     string
+	Metrics  string
+	Traces   string
+	Profiles string
+}
+
+// Render returns the runtime overrides file of each backend, keyed by file
+// name. Every file is rendered, even when no tenant sets a retention for its
+// backend, so that the backends can always mount it.
+func Render(tenants []TenantRetention) (map[string]string, error) {
+	loki := map[string]any{}
+	mimir := map[string]any{}
+	tempo := map[string]any{}
+	pyroscope := map[string]any{}
+
+	for _, t := range tenants {
+		if t.Logs != "" {
+			d, err := promDuration(t.Logs)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot render log retention of tenant %s", t.TenantID)
+			}
+			loki[t.TenantID] = map[string]any{"retention_period": d}
+		}
+		if t.Metrics != "" {
+			d, err := promDuration(t.Metrics)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot render metric retention of tenant %s", t.TenantID)
+			}
+			mimir[t.TenantID] = map[string]any{"compactor_blocks_retention_period": d}
+		}
+		if t.Traces != "" {
+			d, err := goDuration(t.Traces)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot render trace retention of tenant %s", t.TenantID)
+			}
+			tempo[t.TenantID] = map[string]any{"compaction": map[string]any{"block_retention": d}}
+		}
+		if t.Profiles != "" {
+			d, err := promDuration(t.Profiles)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot render profile retention of tenant %s", t.TenantID)
+			}
+			pyroscope[t.TenantID] = map[string]any{"compactor_blocks_retention_period": d}
+		}
+	}
+
+	files := map[string]string{}
+	for key, overrides := range map[string]map[string]any{
+		KeyLoki:      loki,
+		KeyMimir:     mimir,
+		KeyTempo:     tempo,
+		KeyPyroscope: pyroscope,
+	} {
+		// sigs.k8s.io/yaml sorts map keys, so the output is deterministic.
+		b, err := yaml.Marshal(map[string]any{"overrides": overrides})
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot render %s", key)
+		}
+		files[key] = string(b)
+	}
+	return files, nil
+}
+
+// promDuration converts a retention duration to the Prometheus duration
+// format used by Loki, Mimir and Pyroscope, where m means minutes.
+func promDuration(d string) (string, error) {
+	n, unit, err := parseDuration(d)
+	if err != nil {
+		return "", err
+	}
+	if unit == "m" {
+		return strconv.Itoa(n*30) + "d", nil
+	}
+	return d, nil
+}
+
+// goDuration converts a retention duration to the Go duration format Tempo
+// expects, which has no day, week, month or year units.
+func goDuration(d string) (string, error) {
+	n, unit, err := parseDuration(d)
+	if err != nil {
+		return "", err
+	}
+	hours := map[string]int{"h": 1, "d": 24, "w": 24 * 7, "m": 24 * 30, "y": 24 * 365}
+	return strconv.Itoa(n*hours[unit]) + "h", nil
+}
+
+// parseDuration splits a retention duration into its value and unit.
+func parseDuration(d string) (int, string, error) {
+	if len(d) < 2 {
+		return 0, "", errors.Errorf("invalid duration %q", d)
+	}
+	n, err := strconv.Atoi(d[:len(d)-1])
+	if err != nil {
+		return 0, "", errors.Errorf("invalid duration %q", d)
+	}
+	unit := d[len(d)-1:]
+	switch unit {
+	case "h", "d", "w", "m", "y":
+		return n, unit, nil
+	default:
+		return 0, "", errors.Errorf("invalid duration %q: unknown unit %q", d, unit)
+	}
+}
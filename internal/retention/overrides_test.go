@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	cases := map[string]struct {
+		tenants []TenantRetention
+		want    map[string]string
+		wantErr bool
+	}{
+		"NoTenants": {
+			want: map[string]string{
+				KeyLoki:      "overrides: {}\n",
+				KeyMimir:     "overrides: {}\n",
+				KeyTempo:     "overrides: {}\n",
+				KeyPyroscope: "overrides: {}\n",
+			},
+		},
+		"AllSignals": {
+			tenants: []TenantRetention{
+				{TenantID: "beta", Logs: "30d", Metrics: "1y", Traces: "2w", Profiles: "7d"},
+				{TenantID: "alpha", Logs: "3m", Traces: "36h"},
+			},
+			want: map[string]string{
+				KeyLoki: "overrides:\n" +
+					"  alpha:\n    retention_period: 90d\n" +
+					"  beta:\n    retention_period: 30d\n",
+				KeyMimir: "overrides:\n" +
+					"  beta:\n    compactor_blocks_retention_period: 1y\n",
+				KeyTempo: "overrides:\n" +
+					"  alpha:\n    compaction:\n      block_retention: 36h\n" +
+					"  beta:\n    compaction:\n      block_retention: 336h\n",
+				KeyPyroscope: "overrides:\n" +
+					"  beta:\n    compactor_blocks_retention_period: 7d\n",
+			},
+		},
+		"InvalidTraceRetention": {
+			tenants: []TenantRetention{{TenantID: "alpha", Traces: "30s"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.tenants)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("Render(...): expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Render(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}